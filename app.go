@@ -3,7 +3,10 @@ package main
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"ksight/pkg/informer"
 	"ksight/pkg/service"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -56,27 +59,124 @@ func (a *App) ToggleClusterPin(clusterID string) error {
 
 // Resource Watcher Methods
 
-// AddResourceWatcher adds a resource watcher for a cluster
-func (a *App) AddResourceWatcher(clusterID, group, version, resource, namespace string) error {
-	request := service.ResourceWatchRequest{
-		ClusterID: clusterID,
-		Group:     group,
-		Version:   version,
-		Resource:  resource,
-		Namespace: namespace,
-	}
+// AddResourceWatcher subscribes to a resource watcher for a cluster and returns the
+// subscription ID. Namespaces is a comma separated list, empty for all namespaces, and
+// metadataOnly watches object metadata only, full objects are loaded with FetchResource
+func (a *App) AddResourceWatcher(clusterID, group, version, resource, namespaces, labelSelector, fieldSelector string, metadataOnly bool) (string, error) {
+	request := newWatchRequest(clusterID, group, version, resource, namespaces, labelSelector, fieldSelector)
+	request.MetadataOnly = metadataOnly
 	return a.clusterService.AddResourceWatcher(request)
 }
 
-// RemoveResourceWatcher removes a resource watcher
-func (a *App) RemoveResourceWatcher(clusterID, group, version, resource string) error {
-	request := service.ResourceWatchRequest{
-		ClusterID: clusterID,
-		Group:     group,
-		Version:   version,
-		Resource:  resource,
+// RemoveResourceWatcher removes a resource watcher subscription
+func (a *App) RemoveResourceWatcher(subscriptionID string) error {
+	return a.clusterService.RemoveResourceWatcher(subscriptionID)
+}
+
+// AddWatcherNamespace adds a namespace to the namespace-scoped watcher of a subscription
+func (a *App) AddWatcherNamespace(subscriptionID, namespace string) (service.ResourceWatchRequest, error) {
+	return a.clusterService.AddWatcherNamespace(subscriptionID, namespace)
+}
+
+// RemoveWatcherNamespace removes a namespace from the namespace-scoped watcher of a subscription
+func (a *App) RemoveWatcherNamespace(subscriptionID, namespace string) (service.ResourceWatchRequest, error) {
+	return a.clusterService.RemoveWatcherNamespace(subscriptionID, namespace)
+}
+
+// SetWatcherGracePeriod sets how many seconds an unused watcher keeps running
+func (a *App) SetWatcherGracePeriod(seconds int) {
+	a.clusterService.SetWatcherGracePeriod(time.Duration(seconds) * time.Second)
+}
+
+// GetWatcherStatus returns the state of every active watcher of a cluster
+func (a *App) GetWatcherStatus(clusterID string) ([]service.WatcherStatus, error) {
+	return a.clusterService.GetWatcherStatus(clusterID)
+}
+
+// AckResourceEvents acknowledges resource events up to and including the given sequence
+func (a *App) AckResourceEvents(sequence uint64) {
+	a.clusterService.AckResourceEvents(sequence)
+}
+
+// SetEventEncoding sets whether updated objects are sent in full or as patches
+func (a *App) SetEventEncoding(encoding string) error {
+	return a.clusterService.SetEventEncoding(encoding)
+}
+
+// ResyncResourceEvents sends the current objects in full when patches miss their base
+func (a *App) ResyncResourceEvents(clusterID, group, version, resource string, metadataOnly bool, objects []string) error {
+	return a.clusterService.ResyncResourceEvents(clusterID, group, version, resource, metadataOnly, objects)
+}
+
+// newWatchRequest builds a watch request from the flat arguments of the watcher methods
+func newWatchRequest(clusterID, group, version, resource, namespaces, labelSelector, fieldSelector string) service.ResourceWatchRequest {
+	return service.ResourceWatchRequest{
+		ClusterID:     clusterID,
+		Group:         group,
+		Version:       version,
+		Resource:      resource,
+		Namespaces:    splitNamespaces(namespaces),
+		LabelSelector: labelSelector,
+		FieldSelector: fieldSelector,
+	}
+}
+
+// splitNamespaces splits a comma separated namespace list
+func splitNamespaces(namespaces string) []string {
+	if namespaces == "" {
+		return nil
 	}
-	return a.clusterService.RemoveResourceWatcher(request)
+	return strings.Split(namespaces, ",")
+}
+
+// FetchResource gets the current full object from the API server
+func (a *App) FetchResource(clusterID, group, version, resource, namespace, name string) (map[string]any, error) {
+	return a.clusterService.FetchResource(clusterID, group, version, resource, namespace, name)
+}
+
+// ListResourceTable lists resources with the columns rendered by the apiserver
+func (a *App) ListResourceTable(clusterID, group, version, resource, namespace, selector string) (*informer.ResourceTable, error) {
+	return a.clusterService.ListResourceTable(clusterID, group, version, resource, namespace, selector)
+}
+
+// WatchResourceTable lists resources as a table and emits row changes as table:event events
+func (a *App) WatchResourceTable(clusterID, group, version, resource, namespace, selector string) (service.TableWatchResult, error) {
+	return a.clusterService.WatchResourceTable(clusterID, group, version, resource, namespace, selector)
+}
+
+// StopResourceTableWatch stops a table watch
+func (a *App) StopResourceTableWatch(watchID string) error {
+	return a.clusterService.StopResourceTableWatch(watchID)
+}
+
+// GetEventsFor returns the Kubernetes events of an object, oldest first
+func (a *App) GetEventsFor(clusterID, group, version, resource, namespace, name string) ([]informer.KubernetesEvent, error) {
+	return a.clusterService.GetEventsFor(clusterID, group, version, resource, namespace, name)
+}
+
+// WatchEventsFor returns the Kubernetes events of an object and emits their changes as events:event events
+func (a *App) WatchEventsFor(clusterID, group, version, resource, namespace, name string) (service.EventsWatchResult, error) {
+	return a.clusterService.WatchEventsFor(clusterID, group, version, resource, namespace, name)
+}
+
+// StopEventsWatch stops an events watch
+func (a *App) StopEventsWatch(watchID string) error {
+	return a.clusterService.StopEventsWatch(watchID)
+}
+
+// QueryEventHistory returns recorded Kubernetes events by object, reason, type and time range
+func (a *App) QueryEventHistory(query informer.EventHistoryQuery) ([]informer.KubernetesEvent, error) {
+	return a.clusterService.QueryEventHistory(query)
+}
+
+// SetEventRetention sets how many hours and how many events the event history keeps
+func (a *App) SetEventRetention(maxAgeHours, maxEvents int) error {
+	return a.clusterService.SetEventRetention(maxAgeHours, maxEvents)
+}
+
+// SetResyncPeriod sets the resync period in seconds of a cluster, or of one resource type when resource is set
+func (a *App) SetResyncPeriod(clusterID, group, version, resource string, seconds int) error {
+	return a.clusterService.SetResyncPeriod(clusterID, group, version, resource, seconds)
 }
 
 // GetResourceTypes returns available resource types for a cluster
@@ -84,6 +184,57 @@ func (a *App) GetResourceTypes(clusterID string) ([]schema.GroupVersionResource,
 	return a.clusterService.GetResourceTypes(clusterID)
 }
 
+// ListResources pages through watched objects with selectors and sorting
+func (a *App) ListResources(query service.ResourceQuery) (*informer.ResourcePage, error) {
+	return a.clusterService.ListResources(query)
+}
+
+// RegisterIndexer adds a JSONPath index to the informers of a resource type
+func (a *App) RegisterIndexer(group, version, resource, name, jsonPath string) error {
+	return a.clusterService.RegisterIndexer(group, version, resource, name, jsonPath)
+}
+
+// GetResourceTree returns the ownership tree around a resource with the readiness of its nodes
+func (a *App) GetResourceTree(clusterID string, group, version, resource, namespace, name string, maxChildren int) (*informer.ResourceTreeNode, error) {
+	return a.clusterService.GetResourceTree(clusterID, group, version, resource, namespace, name, maxChildren)
+}
+
+// GetDependencies returns the config maps, secrets, claims and service account a pod or workload uses
+func (a *App) GetDependencies(clusterID string, group, version, resource, namespace, name string) ([]informer.Dependency, error) {
+	return a.clusterService.GetDependencies(clusterID, group, version, resource, namespace, name)
+}
+
+// GetDependents returns the watched pods and workloads using an object
+func (a *App) GetDependents(clusterID, kind, namespace, name string) ([]informer.Dependent, error) {
+	return a.clusterService.GetDependents(clusterID, kind, namespace, name)
+}
+
+// GetNetworkingFor returns the services, endpoint slices, ingresses and HTTP routes of a pod
+func (a *App) GetNetworkingFor(clusterID, namespace, name string) (*informer.PodNetworking, error) {
+	return a.clusterService.GetNetworkingFor(clusterID, namespace, name)
+}
+
+// ResolveRootControllers returns the root controllers of objects in their order
+func (a *App) ResolveRootControllers(clusterID string, objects []informer.ObjectReference) ([]informer.RootControllerResult, error) {
+	return a.clusterService.ResolveRootControllers(clusterID, objects)
+}
+
+// SetExcludedOwnerKinds sets the owner kinds root controllers are not resolved through
+func (a *App) SetExcludedOwnerKinds(kinds []string) {
+	a.clusterService.SetExcludedOwnerKinds(kinds)
+}
+
+// ResolveResource resolves a resource name, short name or kind to its resource type
+func (a *App) ResolveResource(clusterID, name string) (*informer.ResolvedResource, error) {
+	return a.clusterService.ResolveResource(clusterID, name)
+}
+
+// GetAPIResources returns the resource types of a cluster with their discovery details
+// and the API groups that failed to resolve
+func (a *App) GetAPIResources(clusterID string) (*informer.APIResources, error) {
+	return a.clusterService.GetAPIResources(clusterID)
+}
+
 // Kubeconfig Management Methods
 
 // LoadKubeconfigFromFile loads kubeconfig from file path
@@ -6,9 +6,11 @@ export interface ClusterInfo {
   name: string
   context: string
   server: string
-  status: 'connected' | 'disconnected' | 'error'
+  status: 'connected' | 'degraded' | 'disconnected' | 'error'
   lastError?: string
   isPinned: boolean
+  resyncPeriodSeconds: number
+  suppressedEvents: number
 }
 
 export interface ResourceWatchRequest {
@@ -17,6 +19,34 @@ export interface ResourceWatchRequest {
   version: string
   resource: string
   namespace?: string
+  namespaces?: string[]
+  labelSelector?: string
+  fieldSelector?: string
+  // Objects only carry metadata, load the full object with fetchResource
+  metadataOnly?: boolean
+}
+
+// A watcher that failed to list or watch, failedNamespace is set for namespace scoped watchers
+export interface WatcherError extends ResourceWatchRequest {
+  failedNamespace?: string
+  type: 'unauthorized' | 'forbidden' | 'gone' | 'notfound' | 'network' | 'timeout' | 'unknown'
+  message: string
+  timestamp: string
+}
+
+export interface WatcherStatus extends ResourceWatchRequest {
+  subscriptions: number
+  hasSynced: boolean
+  objectCount: number
+  lastEventTime: string
+  lastResourceVersion: string
+  eventsPerSecond: number
+  lastError?: {
+    namespace?: string
+    type: WatcherError['type']
+    message: string
+    timestamp: string
+  }
 }
 
 export interface GroupVersionResource {
@@ -25,6 +55,141 @@ export interface GroupVersionResource {
   resource: string
 }
 
+// A resource type served by a cluster, in its preferred version
+export interface APIResourceType extends GroupVersionResource {
+  kind: string
+  namespaced: boolean
+  verbs: string[]
+  shortNames?: string[]
+  categories?: string[]
+  subresources?: string[]
+  // All served versions, preferred first
+  versions: string[]
+}
+
+// Reads from the informer caches, a watcher covering the query has to be running
+export interface ResourceQuery {
+  clusterId: string
+  gvr: GroupVersionResource
+  namespace?: string
+  labelSelector?: string
+  fieldSelector?: string
+  nameContains?: string
+  // Selects the objects of an index: 'ownerUID', 'nodeName', 'labelKey', 'image'
+  // or one added with registerIndexer
+  indexName?: string
+  indexValue?: string
+  // Field paths like 'metadata.creationTimestamp', prefixed with '-' to sort descending
+  sortBy?: string[]
+  limit?: number
+  continue?: string
+}
+
+export interface ResourcePage {
+  items: any[]
+  total: number
+  continue?: string
+}
+
+export interface ResolvedResource {
+  gvr: GroupVersionResource
+  gvk: { group: string; version: string; kind: string }
+  namespaced: boolean
+}
+
+export interface ResourceTreeNode {
+  gvr: GroupVersionResource
+  kind: string
+  namespace?: string
+  name: string
+  uid: string
+  status: 'ready' | 'progressing' | 'failed' | 'unknown'
+  message?: string
+  // The resource the tree was requested for
+  focus?: boolean
+  children?: ResourceTreeNode[]
+  // Children left out beyond the limit, shown as "N more items"
+  moreChildren?: number
+}
+
+export type DependencyKind = 'ConfigMap' | 'Secret' | 'PersistentVolumeClaim' | 'ServiceAccount'
+
+export interface Dependency {
+  kind: DependencyKind
+  name: string
+  // Where the object is used, like 'volume config' or 'env DB_PASSWORD of container app'
+  references: string[]
+}
+
+export interface Dependent {
+  gvr: GroupVersionResource
+  kind: string
+  namespace: string
+  name: string
+  uid: string
+  references: string[]
+}
+
+export interface PodNetworking {
+  services: any[]
+  endpointSlices: any[]
+  ingresses: any[]
+  httpRoutes: any[]
+  // Resource types without a watcher, like 'endpointslices.discovery.k8s.io', their lists are empty
+  unwatched?: string[]
+}
+
+export interface ObjectReference {
+  gvr: GroupVersionResource
+  namespace?: string
+  name: string
+}
+
+// The topmost owner of an object, like the Deployment of a pod
+export interface RootController {
+  gvr: GroupVersionResource
+  kind: string
+  namespace?: string
+  name: string
+  uid: string
+}
+
+export interface RootControllerResult {
+  object: ObjectReference
+  // Missing for objects without an owner
+  root?: RootController
+  error?: string
+}
+
+export interface RootControllersEvent {
+  clusterId: string
+  results: RootControllerResult[]
+}
+
+// An API group version that could not be discovered, e.g. an unavailable metrics server
+export interface DiscoveryFailure {
+  group: string
+  version: string
+  error: string
+}
+
+export interface APIResources {
+  resources: APIResourceType[]
+  failedGroups?: DiscoveryFailure[]
+}
+
+// The scope of the watcher that reported an event
+export interface WatcherKey {
+  gvr: GroupVersionResource
+  // Comma separated, empty for all namespaces
+  namespaces?: string
+  labelSelector?: string
+  fieldSelector?: string
+  metadataOnly?: boolean
+}
+
+// Overlapping watchers report the same changes, each with its own watcher. DELETED
+// from a selector-scoped watcher may mean the object stopped matching the selectors.
 export interface ResourceEvent {
   type: 'ADDED' | 'MODIFIED' | 'DELETED'
   clusterId: string
@@ -34,6 +199,105 @@ export interface ResourceEvent {
   object: any
   oldObject?: any
   timestamp: string
+  metadataOnly?: boolean
+  watcher: WatcherKey
+}
+
+export interface TableColumnDefinition {
+  name: string
+  type: string
+  format: string
+  description: string
+  priority: number
+}
+
+export interface TableRow {
+  namespace?: string
+  name: string
+  uid: string
+  resourceVersion: string
+  cells: any[]
+}
+
+export interface ResourceTable {
+  columns: TableColumnDefinition[]
+  rows: TableRow[]
+  resourceVersion: string
+}
+
+export interface TableWatchResult {
+  watchId: string
+  table: ResourceTable
+}
+
+// RESET replaces the whole table after the watch had to relist
+export interface TableWatchEvent {
+  watchId: string
+  type: 'ADDED' | 'MODIFIED' | 'DELETED' | 'RESET'
+  rows?: TableRow[]
+  table?: ResourceTable
+}
+
+// A core/v1 or events.k8s.io/v1 Event in a common form
+export interface KubernetesEvent {
+  uid: string
+  namespace: string
+  name: string
+  type: 'Normal' | 'Warning' | string
+  reason: string
+  message: string
+  count: number
+  firstTimestamp: string
+  lastTimestamp: string
+  source?: string
+  regarding: {
+    kind: string
+    namespace?: string
+    name: string
+    uid?: string
+    fieldPath?: string
+  }
+}
+
+// Empty fields match all recorded events
+export interface EventHistoryQuery {
+  clusterId: string
+  regardingUid?: string
+  regardingKind?: string
+  regardingNamespace?: string
+  regardingName?: string
+  reason?: string
+  type?: 'Normal' | 'Warning' | string
+  // RFC 3339 timestamps, events last seen at or after since and first seen at or before until
+  since?: string
+  until?: string
+  // Newest events returned
+  limit?: number
+}
+
+export interface EventsWatchResult {
+  watchId: string
+  events: KubernetesEvent[]
+}
+
+export interface EventsWatchEvent {
+  watchId: string
+  type: 'ADDED' | 'MODIFIED' | 'DELETED'
+  event: KubernetesEvent
+}
+
+export interface SequencedResourceEvent extends ResourceEvent {
+  sequence: number
+}
+
+// 'patch' sends updates as JSON merge patches, the SDK applies them before listeners see them
+export type EventEncoding = 'full' | 'patch'
+
+// A resource event as emitted, updates may carry a merge patch against the object
+// at baseResourceVersion instead of the object
+interface EncodedResourceEvent extends SequencedResourceEvent {
+  patch?: any
+  baseResourceVersion?: string
 }
 
 // Wails backend method calls
@@ -46,9 +310,36 @@ declare global {
           RemoveCluster(clusterId: string): Promise<void>
           GetClusters(): Promise<Record<string, ClusterInfo>>
           ToggleClusterPin(clusterId: string): Promise<void>
-          AddResourceWatcher(clusterId: string, group: string, version: string, resource: string, namespace: string): Promise<void>
-          RemoveResourceWatcher(clusterId: string, group: string, version: string, resource: string): Promise<void>
+          AddResourceWatcher(clusterId: string, group: string, version: string, resource: string, namespaces: string, labelSelector: string, fieldSelector: string, metadataOnly: boolean): Promise<string>
+          RemoveResourceWatcher(subscriptionId: string): Promise<void>
+          AddWatcherNamespace(subscriptionId: string, namespace: string): Promise<ResourceWatchRequest>
+          RemoveWatcherNamespace(subscriptionId: string, namespace: string): Promise<ResourceWatchRequest>
+          SetWatcherGracePeriod(seconds: number): Promise<void>
+          GetWatcherStatus(clusterId: string): Promise<WatcherStatus[]>
+          AckResourceEvents(sequence: number): Promise<void>
+          SetEventEncoding(encoding: EventEncoding): Promise<void>
+          ResyncResourceEvents(clusterId: string, group: string, version: string, resource: string, metadataOnly: boolean, objects: string[]): Promise<void>
+          FetchResource(clusterId: string, group: string, version: string, resource: string, namespace: string, name: string): Promise<any>
+          ListResourceTable(clusterId: string, group: string, version: string, resource: string, namespace: string, selector: string): Promise<ResourceTable>
+          WatchResourceTable(clusterId: string, group: string, version: string, resource: string, namespace: string, selector: string): Promise<TableWatchResult>
+          StopResourceTableWatch(watchId: string): Promise<void>
+          GetEventsFor(clusterId: string, group: string, version: string, resource: string, namespace: string, name: string): Promise<KubernetesEvent[]>
+          WatchEventsFor(clusterId: string, group: string, version: string, resource: string, namespace: string, name: string): Promise<EventsWatchResult>
+          StopEventsWatch(watchId: string): Promise<void>
+          QueryEventHistory(query: EventHistoryQuery): Promise<KubernetesEvent[]>
+          SetEventRetention(maxAgeHours: number, maxEvents: number): Promise<void>
+          SetResyncPeriod(clusterId: string, group: string, version: string, resource: string, seconds: number): Promise<void>
           GetResourceTypes(clusterId: string): Promise<GroupVersionResource[]>
+          GetAPIResources(clusterId: string): Promise<APIResources>
+          ResolveResource(clusterId: string, name: string): Promise<ResolvedResource>
+          ListResources(query: ResourceQuery): Promise<ResourcePage>
+          RegisterIndexer(group: string, version: string, resource: string, name: string, jsonPath: string): Promise<void>
+          GetDependencies(clusterId: string, group: string, version: string, resource: string, namespace: string, name: string): Promise<Dependency[]>
+          GetDependents(clusterId: string, kind: DependencyKind, namespace: string, name: string): Promise<Dependent[]>
+          GetNetworkingFor(clusterId: string, namespace: string, name: string): Promise<PodNetworking>
+          ResolveRootControllers(clusterId: string, objects: ObjectReference[]): Promise<RootControllerResult[]>
+          SetExcludedOwnerKinds(kinds: string[]): Promise<void>
+          GetResourceTree(clusterId: string, group: string, version: string, resource: string, namespace: string, name: string, maxChildren: number): Promise<ResourceTreeNode>
           LoadKubeconfigFromFile(filePath: string): Promise<string>
           SaveKubeconfigToFile(content: string, fileName: string): Promise<string>
           GetKubeconfigFiles(): Promise<string[]>
@@ -64,12 +355,22 @@ declare global {
 export class K8sSDK {
   private eventListeners: Map<string, Set<Function>> = new Map()
 
+  // With patch encoding the last object of each watched object is kept to apply patches to
+  private eventEncoding: EventEncoding = 'full'
+  private patchBases: Map<string, any> = new Map()
+  private decodedBatches: WeakMap<EncodedResourceEvent[], SequencedResourceEvent[]> = new WeakMap()
+
   async addCluster(name: string, kubeconfig: string, context: string = ''): Promise<string> {
     return window.go.main.App.AddCluster(name, kubeconfig, context)
   }
 
   async removeCluster(clusterId: string): Promise<void> {
-    return window.go.main.App.RemoveCluster(clusterId)
+    await window.go.main.App.RemoveCluster(clusterId)
+    for (const key of this.patchBases.keys()) {
+      if (key.startsWith(clusterId + '|')) {
+        this.patchBases.delete(key)
+      }
+    }
   }
 
   async getClusters(): Promise<Record<string, ClusterInfo>> {
@@ -80,29 +381,150 @@ export class K8sSDK {
     return window.go.main.App.ToggleClusterPin(clusterId)
   }
 
-  async addResourceWatcher(request: ResourceWatchRequest): Promise<void> {
+  // Returns a subscription ID, the watcher is shared with other subscriptions of the same scope
+  async addResourceWatcher(request: ResourceWatchRequest): Promise<string> {
     return window.go.main.App.AddResourceWatcher(
       request.clusterId,
       request.group,
       request.version,
       request.resource,
-      request.namespace || ''
+      this.joinNamespaces(request),
+      request.labelSelector || '',
+      request.fieldSelector || '',
+      request.metadataOnly || false
     )
   }
 
-  async removeResourceWatcher(request: Omit<ResourceWatchRequest, 'namespace'>): Promise<void> {
-    return window.go.main.App.RemoveResourceWatcher(
-      request.clusterId,
-      request.group,
-      request.version,
-      request.resource
-    )
+  async removeResourceWatcher(subscriptionId: string): Promise<void> {
+    return window.go.main.App.RemoveResourceWatcher(subscriptionId)
+  }
+
+  async addWatcherNamespace(subscriptionId: string, namespace: string): Promise<ResourceWatchRequest> {
+    return window.go.main.App.AddWatcherNamespace(subscriptionId, namespace)
+  }
+
+  async removeWatcherNamespace(subscriptionId: string, namespace: string): Promise<ResourceWatchRequest> {
+    return window.go.main.App.RemoveWatcherNamespace(subscriptionId, namespace)
+  }
+
+  async setWatcherGracePeriod(seconds: number): Promise<void> {
+    return window.go.main.App.SetWatcherGracePeriod(seconds)
+  }
+
+  // Patches cut the size of updates of large objects, listeners still get full objects
+  async setEventEncoding(encoding: EventEncoding): Promise<void> {
+    await window.go.main.App.SetEventEncoding(encoding)
+    this.eventEncoding = encoding
+    this.patchBases.clear()
+  }
+
+  async getWatcherStatus(clusterId: string): Promise<WatcherStatus[]> {
+    return window.go.main.App.GetWatcherStatus(clusterId)
+  }
+
+  async fetchResource(clusterId: string, gvr: GroupVersionResource, namespace: string, name: string): Promise<any> {
+    return window.go.main.App.FetchResource(clusterId, gvr.group, gvr.version, gvr.resource, namespace, name)
+  }
+
+  async listResourceTable(clusterId: string, gvr: GroupVersionResource, namespace: string = '', selector: string = ''): Promise<ResourceTable> {
+    return window.go.main.App.ListResourceTable(clusterId, gvr.group, gvr.version, gvr.resource, namespace, selector)
+  }
+
+  async watchResourceTable(clusterId: string, gvr: GroupVersionResource, namespace: string = '', selector: string = ''): Promise<TableWatchResult> {
+    return window.go.main.App.WatchResourceTable(clusterId, gvr.group, gvr.version, gvr.resource, namespace, selector)
+  }
+
+  async stopResourceTableWatch(watchId: string): Promise<void> {
+    return window.go.main.App.StopResourceTableWatch(watchId)
+  }
+
+  // Events of all namespaces are watched once per cluster and shared by all lookups
+  async getEventsFor(clusterId: string, gvr: GroupVersionResource, namespace: string, name: string): Promise<KubernetesEvent[]> {
+    return window.go.main.App.GetEventsFor(clusterId, gvr.group, gvr.version, gvr.resource, namespace, name)
+  }
+
+  // Later changes arrive through onEventsEvent until stopEventsWatch is called
+  async watchEventsFor(clusterId: string, gvr: GroupVersionResource, namespace: string, name: string): Promise<EventsWatchResult> {
+    return window.go.main.App.WatchEventsFor(clusterId, gvr.group, gvr.version, gvr.resource, namespace, name)
+  }
+
+  async stopEventsWatch(watchId: string): Promise<void> {
+    return window.go.main.App.StopEventsWatch(watchId)
+  }
+
+  // Watched events are recorded, so they are found after the cluster deleted them
+  async queryEventHistory(query: EventHistoryQuery): Promise<KubernetesEvent[]> {
+    return window.go.main.App.QueryEventHistory(query)
+  }
+
+  // Zero keeps events of any age or any number of them
+  async setEventRetention(maxAgeHours: number, maxEvents: number): Promise<void> {
+    return window.go.main.App.SetEventRetention(maxAgeHours, maxEvents)
+  }
+
+  // Without a resource the period applies to the whole cluster
+  async setResyncPeriod(clusterId: string, seconds: number, gvr?: GroupVersionResource): Promise<void> {
+    return window.go.main.App.SetResyncPeriod(clusterId, gvr?.group || '', gvr?.version || '', gvr?.resource || '', seconds)
   }
 
   async getResourceTypes(clusterId: string): Promise<GroupVersionResource[]> {
     return window.go.main.App.GetResourceTypes(clusterId)
   }
 
+  async getAPIResources(clusterId: string): Promise<APIResources> {
+    return window.go.main.App.GetAPIResources(clusterId)
+  }
+
+  async listResources(query: ResourceQuery): Promise<ResourcePage> {
+    return window.go.main.App.ListResources(query)
+  }
+
+  // Indexes the values a JSONPath expression like '{.spec.serviceAccountName}' finds
+  async registerIndexer(gvr: GroupVersionResource, name: string, jsonPath: string): Promise<void> {
+    return window.go.main.App.RegisterIndexer(gvr.group, gvr.version, gvr.resource, name, jsonPath)
+  }
+
+  // What a pod or workload uses
+  async getDependencies(clusterId: string, gvr: GroupVersionResource, namespace: string, name: string): Promise<Dependency[]> {
+    return window.go.main.App.GetDependencies(clusterId, gvr.group, gvr.version, gvr.resource, namespace, name)
+  }
+
+  // Who uses an object, only watched pods and workloads are found
+  async getDependents(clusterId: string, kind: DependencyKind, namespace: string, name: string): Promise<Dependent[]> {
+    return window.go.main.App.GetDependents(clusterId, kind, namespace, name)
+  }
+
+  // Reads from the informer caches, watch services, endpointslices, ingresses and httproutes first
+  async getNetworkingFor(clusterId: string, namespace: string, name: string): Promise<PodNetworking> {
+    return window.go.main.App.GetNetworkingFor(clusterId, namespace, name)
+  }
+
+  // Results are in the order of the objects, onRootControllersUpdated reports later changes
+  async resolveRootControllers(clusterId: string, objects: ObjectReference[]): Promise<RootControllerResult[]> {
+    return window.go.main.App.ResolveRootControllers(clusterId, objects)
+  }
+
+  // Kinds like 'ReplicaSet' or 'Rollout.argoproj.io', resolution stops below them
+  async setExcludedOwnerKinds(kinds: string[]): Promise<void> {
+    return window.go.main.App.SetExcludedOwnerKinds(kinds)
+  }
+
+  // Starts at the root owner of the resource, nodes list at most maxChildren children
+  async getResourceTree(clusterId: string, gvr: GroupVersionResource, namespace: string, name: string, maxChildren = 0): Promise<ResourceTreeNode> {
+    return window.go.main.App.GetResourceTree(clusterId, gvr.group, gvr.version, gvr.resource, namespace, name, maxChildren)
+  }
+
+  // Accepts resources, short names and kinds: 'po', 'deploy', 'Deployment', 'deployments.v1.apps'
+  async resolveResource(clusterId: string, name: string): Promise<ResolvedResource> {
+    return window.go.main.App.ResolveResource(clusterId, name)
+  }
+
+  // Resolves a kind of an apiVersion, like k.resource('apps/v1', 'Deployment')
+  async resolveKind(clusterId: string, apiVersion: string, kind: string): Promise<ResolvedResource> {
+    const [group, version] = apiVersion.includes('/') ? apiVersion.split('/') : ['', apiVersion]
+    return this.resolveResource(clusterId, group ? `${kind}.${version}.${group}` : kind)
+  }
+
   async loadKubeconfigFromFile(filePath: string): Promise<string> {
     return window.go.main.App.LoadKubeconfigFromFile(filePath)
   }
@@ -131,8 +553,94 @@ export class K8sSDK {
     return this.addEventListener('cluster:updated', callback)
   }
 
+  // Events arrive in batches, each batch is acknowledged once handled so the
+  // backend can hold back further batches while the UI is busy
+  onResourceEvents(callback: (events: SequencedResourceEvent[]) => void): () => void {
+    return this.addEventListener('resource:events', (events: EncodedResourceEvent[]) => {
+      callback(this.decodeResourceEvents(events))
+      if (events.length > 0) {
+        window.go.main.App.AckResourceEvents(events[events.length - 1].sequence)
+      }
+    })
+  }
+
+  // Fired when CRDs or APIServices change, reload the resource types of the cluster
+  onResourceTypesChanged(callback: (clusterId: string) => void): () => void {
+    return this.addEventListener('resourcetypes:changed', callback)
+  }
+
+  onRootControllersUpdated(callback: (event: RootControllersEvent) => void): () => void {
+    return this.addEventListener('rootcontrollers:updated', callback)
+  }
+
+  onWatcherError(callback: (error: WatcherError) => void): () => void {
+    return this.addEventListener('watcher:error', callback)
+  }
+
+  onEventsEvent(callback: (event: EventsWatchEvent) => void): () => void {
+    return this.addEventListener('events:event', callback)
+  }
+
+  onTableEvent(callback: (event: TableWatchEvent) => void): () => void {
+    return this.addEventListener('table:event', callback)
+  }
+
   onResourceEvent(callback: (event: ResourceEvent) => void): () => void {
-    return this.addEventListener('resource:event', callback)
+    return this.onResourceEvents(events => events.forEach(callback))
+  }
+
+  // Applies the patches of a batch once for all listeners. Patches whose base was
+  // missed are dropped and their objects resynced, they arrive in full later.
+  private decodeResourceEvents(events: EncodedResourceEvent[]): SequencedResourceEvent[] {
+    const cached = this.decodedBatches.get(events)
+    if (cached) {
+      return cached
+    }
+
+    const decoded: SequencedResourceEvent[] = []
+    const resyncs = new Map<string, { event: EncodedResourceEvent, objects: string[] }>()
+    for (const event of events) {
+      const { patch, baseResourceVersion, ...resourceEvent } = event
+      const objectKey = event.namespace ? `${event.namespace}/${event.name}` : event.name
+      const { gvr, namespaces, labelSelector, fieldSelector, metadataOnly } = event.watcher
+      const scopeKey = `${event.clusterId}|${gvr.group}/${gvr.version}/${gvr.resource}|${namespaces || ''}|${labelSelector || ''}|${fieldSelector || ''}|${metadataOnly ? 'metadata' : 'full'}`
+      const key = `${scopeKey}|${objectKey}`
+      const base = this.patchBases.get(key)
+
+      if (patch === undefined) {
+        if (this.eventEncoding === 'patch') {
+          if (event.type === 'DELETED') {
+            this.patchBases.delete(key)
+          } else {
+            this.patchBases.set(key, event.object)
+          }
+        }
+        decoded.push({ ...resourceEvent, oldObject: resourceEvent.oldObject ?? base })
+        continue
+      }
+
+      if (base?.metadata?.resourceVersion !== baseResourceVersion) {
+        const resync = resyncs.get(scopeKey) || { event, objects: [] }
+        resync.objects.push(objectKey)
+        resyncs.set(scopeKey, resync)
+        continue
+      }
+
+      const object = applyMergePatch(base, patch)
+      this.patchBases.set(key, object)
+      decoded.push({ ...resourceEvent, object, oldObject: base })
+    }
+
+    resyncs.forEach(({ event, objects }) => {
+      window.go.main.App.ResyncResourceEvents(event.clusterId, event.gvr.group, event.gvr.version, event.gvr.resource, event.metadataOnly || false, objects)
+    })
+
+    this.decodedBatches.set(events, decoded)
+    return decoded
+  }
+
+  private joinNamespaces(request: ResourceWatchRequest): string {
+    return [request.namespace, ...(request.namespaces || [])].filter(Boolean).join(',')
   }
 
   private addEventListener(eventName: string, callback: Function): () => void {
@@ -175,6 +683,23 @@ export class K8sSDK {
   }
 }
 
+// Applies a JSON merge patch (RFC 7386), unchanged parts are shared with the target
+function applyMergePatch(target: any, patch: any): any {
+  if (patch === null || typeof patch !== 'object' || Array.isArray(patch)) {
+    return patch
+  }
+
+  const result = target !== null && typeof target === 'object' && !Array.isArray(target) ? { ...target } : {}
+  for (const [key, value] of Object.entries(patch)) {
+    if (value === null) {
+      delete result[key]
+    } else {
+      result[key] = applyMergePatch(result[key], value)
+    }
+  }
+  return result
+}
+
 // Global SDK instance
 export const k8s = new K8sSDK()
 
@@ -0,0 +1,235 @@
+package informer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Kinds of the objects pods depend on
+const (
+	DependencyConfigMap      = "ConfigMap"
+	DependencySecret         = "Secret"
+	DependencyPVC            = "PersistentVolumeClaim"
+	DependencyServiceAccount = "ServiceAccount"
+)
+
+// Dependency is an object of its own namespace a pod or pod template uses
+type Dependency struct {
+	Kind string `json:"kind"` // ConfigMap, Secret, PersistentVolumeClaim or ServiceAccount
+	Name string `json:"name"`
+	// References describe where the object is used, like "volume config" or "env DB_PASSWORD of container app"
+	References []string `json:"references"`
+}
+
+// Dependent is a pod or workload that uses an object
+type Dependent struct {
+	GVR        schema.GroupVersionResource `json:"gvr"`
+	Kind       string                      `json:"kind"`
+	Namespace  string                      `json:"namespace"`
+	Name       string                      `json:"name"`
+	UID        string                      `json:"uid"`
+	References []string                    `json:"references"`
+}
+
+// GetDependencies returns the config maps, secrets, persistent volume claims and
+// service account a pod or the pod template of a workload uses
+func (im *InformerManager) GetDependencies(clusterID string, gvr schema.GroupVersionResource, namespace, name string) ([]Dependency, error) {
+	cluster, err := im.getCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := im.getObject(context.TODO(), cluster, gvr, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return objectDependencies(obj), nil
+}
+
+// GetDependents returns the watched pods and workloads that use an object, like the
+// deployments mounting a config map. Objects no watcher holds are not found.
+func (im *InformerManager) GetDependents(clusterID, kind, namespace, name string) ([]Dependent, error) {
+	switch kind {
+	case DependencyConfigMap, DependencySecret, DependencyPVC, DependencyServiceAccount:
+	default:
+		return nil, fmt.Errorf("pods do not depend on objects of kind %s", kind)
+	}
+
+	cluster, err := im.getCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[types.UID]struct{})
+	dependents := []Dependent{}
+	for _, watcher := range cluster.watcherSnapshot() {
+		// Metadata-only objects carry no pod specs
+		key := watcher.currentKey()
+		if key.MetadataOnly {
+			continue
+		}
+
+		items, err := watcher.byIndex(IndexDependency, kind+"/"+name)
+		if err != nil {
+			continue
+		}
+		for _, item := range items {
+			obj, ok := item.(*unstructured.Unstructured)
+			if !ok || obj.GetNamespace() != namespace {
+				continue
+			}
+			if _, exists := seen[obj.GetUID()]; exists {
+				continue
+			}
+			seen[obj.GetUID()] = struct{}{}
+
+			dependent := Dependent{
+				GVR:       key.GVR,
+				Kind:      obj.GetKind(),
+				Namespace: obj.GetNamespace(),
+				Name:      obj.GetName(),
+				UID:       string(obj.GetUID()),
+			}
+			for _, dependency := range objectDependencies(obj) {
+				if dependency.Kind == kind && dependency.Name == name {
+					dependent.References = dependency.References
+				}
+			}
+			dependents = append(dependents, dependent)
+		}
+	}
+
+	sort.Slice(dependents, func(i, j int) bool {
+		if dependents[i].Kind != dependents[j].Kind {
+			return dependents[i].Kind < dependents[j].Kind
+		}
+		return dependents[i].Name < dependents[j].Name
+	})
+	return dependents, nil
+}
+
+// indexDependency indexes pods and workloads by the kind/name of the objects they use
+func indexDependency(obj any) ([]string, error) {
+	object, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+
+	var values []string
+	for _, dependency := range objectDependencies(object) {
+		values = append(values, dependency.Kind+"/"+dependency.Name)
+	}
+	return values, nil
+}
+
+// objectDependencies returns the objects the pod specs of a pod or workload use,
+// in the order they are referenced
+func objectDependencies(obj *unstructured.Unstructured) []Dependency {
+	var dependencies []Dependency
+	positions := make(map[string]int)
+	add := func(kind, name, reference string) {
+		if name == "" {
+			return
+		}
+		key := kind + "/" + name
+		if i, exists := positions[key]; exists {
+			dependencies[i].References = append(dependencies[i].References, reference)
+			return
+		}
+		positions[key] = len(dependencies)
+		dependencies = append(dependencies, Dependency{Kind: kind, Name: name, References: []string{reference}})
+	}
+
+	for _, path := range podSpecPaths {
+		podSpec, found, _ := unstructured.NestedMap(obj.Object, path...)
+		if !found || !isPodSpec(podSpec) {
+			continue
+		}
+
+		serviceAccount, _, _ := unstructured.NestedString(podSpec, "serviceAccountName")
+		if serviceAccount == "" {
+			serviceAccount = "default" // Assigned by the apiserver when pods are created
+		}
+		add(DependencyServiceAccount, serviceAccount, "serviceAccountName")
+
+		imagePullSecrets, _, _ := unstructured.NestedSlice(podSpec, "imagePullSecrets")
+		for _, item := range imagePullSecrets {
+			if secret, ok := item.(map[string]any); ok {
+				name, _ := secret["name"].(string)
+				add(DependencySecret, name, "imagePullSecrets")
+			}
+		}
+
+		volumes, _, _ := unstructured.NestedSlice(podSpec, "volumes")
+		for _, item := range volumes {
+			volume, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			reference := fmt.Sprintf("volume %v", volume["name"])
+
+			configMap, _, _ := unstructured.NestedString(volume, "configMap", "name")
+			add(DependencyConfigMap, configMap, reference)
+			secret, _, _ := unstructured.NestedString(volume, "secret", "secretName")
+			add(DependencySecret, secret, reference)
+			claim, _, _ := unstructured.NestedString(volume, "persistentVolumeClaim", "claimName")
+			add(DependencyPVC, claim, reference)
+
+			sources, _, _ := unstructured.NestedSlice(volume, "projected", "sources")
+			for _, item := range sources {
+				if source, ok := item.(map[string]any); ok {
+					configMap, _, _ := unstructured.NestedString(source, "configMap", "name")
+					add(DependencyConfigMap, configMap, reference)
+					secret, _, _ := unstructured.NestedString(source, "secret", "name")
+					add(DependencySecret, secret, reference)
+				}
+			}
+		}
+
+		for _, field := range []string{"initContainers", "containers", "ephemeralContainers"} {
+			containers, _, _ := unstructured.NestedSlice(podSpec, field)
+			for _, item := range containers {
+				container, ok := item.(map[string]any)
+				if !ok {
+					continue
+				}
+				containerName, _ := container["name"].(string)
+
+				envFrom, _, _ := unstructured.NestedSlice(container, "envFrom")
+				for _, item := range envFrom {
+					if source, ok := item.(map[string]any); ok {
+						reference := "envFrom of container " + containerName
+						configMap, _, _ := unstructured.NestedString(source, "configMapRef", "name")
+						add(DependencyConfigMap, configMap, reference)
+						secret, _, _ := unstructured.NestedString(source, "secretRef", "name")
+						add(DependencySecret, secret, reference)
+					}
+				}
+
+				env, _, _ := unstructured.NestedSlice(container, "env")
+				for _, item := range env {
+					if variable, ok := item.(map[string]any); ok {
+						reference := fmt.Sprintf("env %v of container %s", variable["name"], containerName)
+						configMap, _, _ := unstructured.NestedString(variable, "valueFrom", "configMapKeyRef", "name")
+						add(DependencyConfigMap, configMap, reference)
+						secret, _, _ := unstructured.NestedString(variable, "valueFrom", "secretKeyRef", "name")
+						add(DependencySecret, secret, reference)
+					}
+				}
+			}
+		}
+	}
+	return dependencies
+}
+
+// isPodSpec reports whether a spec found at a pod spec path is one, the spec of
+// a workload is found at the path of a pod spec too
+func isPodSpec(spec map[string]any) bool {
+	_, hasContainers := spec["containers"]
+	return hasContainers
+}
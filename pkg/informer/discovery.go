@@ -0,0 +1,319 @@
+package informer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/disk"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+// discoveryCacheTTL is how long discovery documents cached on disk are used.
+// While a cluster is connected, CRD and APIService changes refresh them right away.
+const discoveryCacheTTL = 10 * time.Minute
+
+// discoveryRefreshDelay collects bursts of API changes, like a chart installing
+// many CRDs, into a single discovery refresh
+const discoveryRefreshDelay = 500 * time.Millisecond
+
+// apiDefinitionResources are the resources whose changes add or remove API resource types
+var apiDefinitionResources = []schema.GroupVersionResource{
+	{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"},
+	{Group: "apiregistration.k8s.io", Version: "v1", Resource: "apiservices"},
+}
+
+// unsafeFileCharacters matches characters not allowed in discovery cache directory names
+var unsafeFileCharacters = regexp.MustCompile(`[^\w/.]`)
+
+// APIResourceType describes a resource type served by a cluster, in its preferred version
+type APIResourceType struct {
+	Group        string   `json:"group"`
+	Version      string   `json:"version"`
+	Resource     string   `json:"resource"`
+	Kind         string   `json:"kind"`
+	Namespaced   bool     `json:"namespaced"`
+	Verbs        []string `json:"verbs"`
+	ShortNames   []string `json:"shortNames,omitempty"`
+	Categories   []string `json:"categories,omitempty"`
+	Subresources []string `json:"subresources,omitempty"` // e.g. status, scale, log
+	Versions     []string `json:"versions"`               // all served versions, preferred first
+}
+
+// DiscoveryFailure is an API group version whose resources could not be discovered,
+// usually because the aggregated API server behind it is unavailable
+type DiscoveryFailure struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Error   string `json:"error"`
+}
+
+// APIResources are the resource types discovered in a cluster. Groups that failed
+// to resolve are listed separately, the resource types of all others are complete.
+type APIResources struct {
+	Resources    []APIResourceType  `json:"resources"`
+	FailedGroups []DiscoveryFailure `json:"failedGroups,omitempty"`
+}
+
+// GVR returns the group, version and resource of the resource type
+func (t APIResourceType) GVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: t.Group, Version: t.Version, Resource: t.Resource}
+}
+
+// newCachedDiscoveryClient creates a discovery client that caches discovery
+// documents in memory and in a directory per API server below cacheDir
+func newCachedDiscoveryClient(config *rest.Config, cacheDir string) (discovery.CachedDiscoveryInterface, error) {
+	host := strings.TrimPrefix(strings.TrimPrefix(config.Host, "https://"), "http://")
+	discoveryDir := filepath.Join(cacheDir, unsafeFileCharacters.ReplaceAllString(host, "_"))
+
+	return disk.NewCachedDiscoveryClientForConfig(config, discoveryDir, "", discoveryCacheTTL)
+}
+
+// SetResourceTypesHandler sets the handler called with the cluster ID whenever
+// the resource types served by a cluster may have changed
+func (im *InformerManager) SetResourceTypesHandler(handler func(clusterID string)) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.resourceTypesHandler = handler
+}
+
+// GetResourceTypes returns the preferred version of every resource type served by a cluster.
+// Resource types of groups that failed to resolve are left out.
+func (im *InformerManager) GetResourceTypes(clusterID string) ([]schema.GroupVersionResource, error) {
+	apiResources, err := im.GetAPIResources(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	gvrs := make([]schema.GroupVersionResource, 0, len(apiResources.Resources))
+	for _, resourceType := range apiResources.Resources {
+		gvrs = append(gvrs, resourceType.GVR())
+	}
+	return gvrs, nil
+}
+
+// GetAPIResources returns the resource types served by a cluster. A complete result
+// is kept in memory until CRDs or APIServices change, while a partial one is
+// rediscovered on the next lookup so failed groups are retried.
+func (im *InformerManager) GetAPIResources(clusterID string) (*APIResources, error) {
+	cluster, err := im.getCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster.discoveryMu.Lock()
+	defer cluster.discoveryMu.Unlock()
+
+	if cluster.apiResources != nil {
+		return cluster.apiResources, nil
+	}
+
+	groups, resourceLists, err := cluster.Discovery.ServerGroupsAndResources()
+	failedGroups, err := discoveryFailures(err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover resources: %w", err)
+	}
+
+	apiResources := &APIResources{
+		Resources:    buildAPIResources(groups, resourceLists),
+		FailedGroups: failedGroups,
+	}
+	if len(failedGroups) == 0 {
+		cluster.apiResources = apiResources
+	}
+	return apiResources, nil
+}
+
+// discoveryFailures returns the failed group versions of a partial discovery, or
+// the error itself if discovery failed completely
+func discoveryFailures(err error) ([]DiscoveryFailure, error) {
+	if err == nil {
+		return nil, nil
+	}
+
+	var groupErr *discovery.ErrGroupDiscoveryFailed
+	if !errors.As(err, &groupErr) {
+		return nil, err
+	}
+
+	failures := make([]DiscoveryFailure, 0, len(groupErr.Groups))
+	for gv, gvErr := range groupErr.Groups {
+		failures = append(failures, DiscoveryFailure{
+			Group:   gv.Group,
+			Version: gv.Version,
+			Error:   gvErr.Error(),
+		})
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		if failures[i].Group != failures[j].Group {
+			return failures[i].Group < failures[j].Group
+		}
+		return failures[i].Version < failures[j].Version
+	})
+	return failures, nil
+}
+
+// buildAPIResources returns the resource types of the discovered groups in their
+// preferred version, with the subresources and other versions they are served in
+func buildAPIResources(groups []*metav1.APIGroup, resourceLists []*metav1.APIResourceList) []APIResourceType {
+	listsByVersion := make(map[string]*metav1.APIResourceList, len(resourceLists))
+	for _, resourceList := range resourceLists {
+		listsByVersion[resourceList.GroupVersion] = resourceList
+	}
+
+	result := []APIResourceType{}
+	for _, group := range groups {
+		// Resources take the preferred version of their group if it serves them
+		versions := []metav1.GroupVersionForDiscovery{group.PreferredVersion}
+		for _, version := range group.Versions {
+			if version.Version != group.PreferredVersion.Version {
+				versions = append(versions, version)
+			}
+		}
+
+		indexes := make(map[string]int) // resource -> index in result
+		for _, version := range versions {
+			resourceList, exists := listsByVersion[version.GroupVersion]
+			if !exists {
+				continue
+			}
+
+			subresources := make(map[string][]string)
+			for _, resource := range resourceList.APIResources {
+				if parent, subresource, found := strings.Cut(resource.Name, "/"); found {
+					subresources[parent] = append(subresources[parent], subresource)
+				}
+			}
+
+			for _, resource := range resourceList.APIResources {
+				if strings.Contains(resource.Name, "/") {
+					continue
+				}
+				if index, exists := indexes[resource.Name]; exists {
+					result[index].Versions = append(result[index].Versions, version.Version)
+					continue
+				}
+
+				indexes[resource.Name] = len(result)
+				result = append(result, APIResourceType{
+					Group:        group.Name,
+					Version:      version.Version,
+					Resource:     resource.Name,
+					Kind:         resource.Kind,
+					Namespaced:   resource.Namespaced,
+					Verbs:        resource.Verbs,
+					ShortNames:   resource.ShortNames,
+					Categories:   resource.Categories,
+					Subresources: subresources[resource.Name],
+					Versions:     []string{version.Version},
+				})
+			}
+		}
+	}
+	return result
+}
+
+// watchResourceTypes watches CRDs and APIServices until the context is done and
+// refreshes the discovery model of the cluster whenever they change
+func (im *InformerManager) watchResourceTypes(ctx context.Context, cluster *ClusterConnection) {
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+
+	for _, gvr := range apiDefinitionResources {
+		// Failures are logged when they start or change, not on every retry,
+		// watches of CRDs and APIServices may be forbidden for good
+		var failure string
+		var failureMu sync.Mutex
+		recovered := func() {
+			failureMu.Lock()
+			failure = ""
+			failureMu.Unlock()
+		}
+
+		client := metadataClient{client: cluster.Metadata.Resource(gvr)}
+		listWatch := newResumableListWatch(client, nil, nil, "", func(string) { recovered() }, recovered)
+		informer := cache.NewSharedIndexInformer(listWatch, &unstructured.Unstructured{}, 0, cache.Indexers{})
+
+		// Objects of the initial list are already part of the discovery model
+		informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+			AddFunc: func(obj any, isInInitialList bool) {
+				if !isInInitialList {
+					notify()
+				}
+			},
+			UpdateFunc: func(oldObj, newObj any) { notify() },
+			DeleteFunc: func(obj any) { notify() },
+		})
+		informer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+			errorType := classifyWatchError(err)
+			failureMu.Lock()
+			changed := errorType != failure
+			failure = errorType
+			failureMu.Unlock()
+
+			if changed && ctx.Err() == nil {
+				fmt.Printf("Warning: Failed to watch %s in cluster %s: %v\n", gvr.String(), cluster.ID, err)
+			}
+		})
+
+		go informer.Run(ctx.Done())
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-changed:
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(discoveryRefreshDelay):
+		}
+
+		// Changes during the delay are covered by this refresh
+		select {
+		case <-changed:
+		default:
+		}
+
+		im.refreshResourceTypes(cluster)
+	}
+}
+
+// refreshResourceTypes drops the discovery model of a cluster, so the next lookup
+// rediscovers it from the server, and notifies the resource types handler
+func (im *InformerManager) refreshResourceTypes(cluster *ClusterConnection) {
+	cluster.discoveryMu.Lock()
+	cluster.Discovery.Invalidate()
+	meta.MaybeResetRESTMapper(cluster.Mapper)
+	cluster.apiResources = nil
+	cluster.discoveryMu.Unlock()
+
+	im.mu.RLock()
+	handler := im.resourceTypesHandler
+	im.mu.RUnlock()
+
+	if handler != nil {
+		handler(cluster.ID)
+	}
+}
@@ -0,0 +1,298 @@
+package informer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// eventHistoryPruneInterval is how often the event history is pruned to its retention
+const eventHistoryPruneInterval = 10 * time.Minute
+
+// eventHistoryQueueSize is how many watched events wait to be recorded, and the
+// most events recorded at once
+const eventHistoryQueueSize = 1000
+
+// recordedEvent is a watched event of a cluster for the event history
+type recordedEvent struct {
+	clusterID string
+	event     KubernetesEvent
+}
+
+// EventRetention limits how long and how many events the event history keeps
+type EventRetention struct {
+	MaxAge    time.Duration `json:"maxAge"`    // by the last time an event was seen, zero keeps all
+	MaxEvents int           `json:"maxEvents"` // newest events kept of all clusters, zero keeps all
+}
+
+// DefaultEventRetention keeps events for a month, well past the hour clusters keep them
+var DefaultEventRetention = EventRetention{
+	MaxAge:    30 * 24 * time.Hour,
+	MaxEvents: 100000,
+}
+
+// EventHistoryQuery selects events of the event history. Empty fields match all events.
+type EventHistoryQuery struct {
+	ClusterID string `json:"clusterId"`
+	// The object events are about, by UID or by kind, namespace and name
+	RegardingUID       string    `json:"regardingUid,omitempty"`
+	RegardingKind      string    `json:"regardingKind,omitempty"`
+	RegardingNamespace string    `json:"regardingNamespace,omitempty"`
+	RegardingName      string    `json:"regardingName,omitempty"`
+	Reason             string    `json:"reason,omitempty"`
+	Type               string    `json:"type,omitempty"`  // Normal, Warning
+	Since              time.Time `json:"since,omitempty"` // events last seen at or after
+	Until              time.Time `json:"until,omitempty"` // events first seen at or before
+	Limit              int       `json:"limit,omitempty"` // newest events returned, 0 for all
+}
+
+// StoreEvent records an event in the event history. Events are kept once, series
+// and count updates replace the recorded event.
+func (dc *DatabaseCache) StoreEvent(clusterID string, event KubernetesEvent) error {
+	return dc.storeEvents([]recordedEvent{{clusterID: clusterID, event: event}})
+}
+
+// storeEvents records events in the event history in one transaction
+func (dc *DatabaseCache) storeEvents(events []recordedEvent) error {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	tx, err := dc.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Updates arriving out of order must not replace a later count
+	statement, err := tx.Prepare(`
+		INSERT INTO event_history
+		(uid, cluster_id, regarding_uid, regarding_kind, regarding_namespace, regarding_name,
+		 type, reason, count, first_seen, last_seen, data, stored_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(uid) DO UPDATE SET
+			count = excluded.count,
+			last_seen = excluded.last_seen,
+			data = excluded.data,
+			stored_at = CURRENT_TIMESTAMP
+		WHERE excluded.count >= event_history.count AND excluded.last_seen >= event_history.last_seen
+	`)
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	for _, recorded := range events {
+		event := recorded.event
+		data, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal event: %w", err)
+		}
+
+		_, err = statement.Exec(
+			event.UID,
+			recorded.clusterID,
+			event.Regarding.UID,
+			event.Regarding.Kind,
+			event.Regarding.Namespace,
+			event.Regarding.Name,
+			event.Type,
+			event.Reason,
+			event.Count,
+			event.FirstTimestamp.UnixMilli(),
+			event.LastTimestamp.UnixMilli(),
+			string(data),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// QueryEvents returns the recorded events matching a query, oldest first
+func (dc *DatabaseCache) QueryEvents(query EventHistoryQuery) ([]KubernetesEvent, error) {
+	dc.mu.RLock()
+	defer dc.mu.RUnlock()
+
+	var conditions []string
+	var args []any
+	addCondition := func(condition string, value any) {
+		conditions = append(conditions, condition)
+		args = append(args, value)
+	}
+
+	addCondition("cluster_id = ?", query.ClusterID)
+	if query.RegardingUID != "" {
+		addCondition("regarding_uid = ?", query.RegardingUID)
+	}
+	if query.RegardingKind != "" {
+		addCondition("regarding_kind = ?", query.RegardingKind)
+	}
+	if query.RegardingNamespace != "" {
+		addCondition("regarding_namespace = ?", query.RegardingNamespace)
+	}
+	if query.RegardingName != "" {
+		addCondition("regarding_name = ?", query.RegardingName)
+	}
+	if query.Reason != "" {
+		addCondition("reason = ?", query.Reason)
+	}
+	if query.Type != "" {
+		addCondition("type = ?", query.Type)
+	}
+	if !query.Since.IsZero() {
+		addCondition("last_seen >= ?", query.Since.UnixMilli())
+	}
+	if !query.Until.IsZero() {
+		addCondition("first_seen <= ?", query.Until.UnixMilli())
+	}
+
+	statement := "SELECT data FROM event_history WHERE " + strings.Join(conditions, " AND ") + " ORDER BY last_seen DESC, uid"
+	if query.Limit > 0 {
+		statement += " LIMIT ?"
+		args = append(args, query.Limit)
+	}
+
+	rows, err := dc.db.Query(statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []KubernetesEvent{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			continue // Skip invalid entries
+		}
+
+		var event KubernetesEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			continue // Skip invalid entries
+		}
+		events = append(events, event)
+	}
+
+	// Selected newest first so that the limit keeps the latest events
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	return events, rows.Err()
+}
+
+// PruneEvents removes the events the retention does not keep
+func (dc *DatabaseCache) PruneEvents(retention EventRetention) error {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	if retention.MaxAge > 0 {
+		cutoff := time.Now().Add(-retention.MaxAge).UnixMilli()
+		if _, err := dc.db.Exec(`DELETE FROM event_history WHERE last_seen < ?`, cutoff); err != nil {
+			return err
+		}
+	}
+
+	if retention.MaxEvents > 0 {
+		query := `
+			DELETE FROM event_history WHERE uid NOT IN (
+				SELECT uid FROM event_history ORDER BY last_seen DESC LIMIT ?
+			)
+		`
+		if _, err := dc.db.Exec(query, retention.MaxEvents); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// QueryEventHistory returns recorded events, including those the cluster no longer keeps
+func (im *InformerManager) QueryEventHistory(query EventHistoryQuery) ([]KubernetesEvent, error) {
+	if im.dbCache == nil {
+		return nil, fmt.Errorf("cache not available")
+	}
+	return im.dbCache.QueryEvents(query)
+}
+
+// SetEventRetention sets the retention of the event history and prunes it right away
+func (im *InformerManager) SetEventRetention(retention EventRetention) error {
+	im.mu.Lock()
+	im.eventRetention = retention
+	im.mu.Unlock()
+
+	if im.dbCache == nil {
+		return fmt.Errorf("cache not available")
+	}
+	return im.dbCache.PruneEvents(retention)
+}
+
+// recordEventHistory queues a watched core/v1 or events.k8s.io/v1 Event for the
+// event history. It only blocks while the queue is full.
+func (im *InformerManager) recordEventHistory(clusterID string, obj *unstructured.Unstructured) {
+	if im.dbCache == nil {
+		return
+	}
+
+	select {
+	case im.eventHistory <- recordedEvent{clusterID: clusterID, event: newKubernetesEvent(obj)}:
+	case <-im.ctx.Done():
+	}
+}
+
+// writeEventHistory records the queued events until the manager is shut down.
+// Events queued while a batch is written are written together in the next one.
+func (im *InformerManager) writeEventHistory() {
+	for {
+		var batch []recordedEvent
+		select {
+		case <-im.ctx.Done():
+			return
+		case recorded := <-im.eventHistory:
+			batch = append(batch, recorded)
+		}
+
+	queued:
+		for len(batch) < eventHistoryQueueSize {
+			select {
+			case recorded := <-im.eventHistory:
+				batch = append(batch, recorded)
+			default:
+				break queued
+			}
+		}
+
+		if err := im.dbCache.storeEvents(batch); err != nil {
+			fmt.Printf("Warning: Failed to record %d events: %v\n", len(batch), err)
+		}
+	}
+}
+
+// isEventResource reports whether a resource type is core/v1 or events.k8s.io/v1 Events
+func isEventResource(gvr schema.GroupVersionResource) bool {
+	return gvr.GroupResource() == coreEventsResource || gvr.GroupResource() == eventsResource
+}
+
+// pruneEventHistory applies the event retention until the manager is shut down
+func (im *InformerManager) pruneEventHistory() {
+	ticker := time.NewTicker(eventHistoryPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-im.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		im.mu.RLock()
+		retention := im.eventRetention
+		im.mu.RUnlock()
+
+		if err := im.dbCache.PruneEvents(retention); err != nil {
+			fmt.Printf("Warning: Failed to prune event history: %v\n", err)
+		}
+	}
+}
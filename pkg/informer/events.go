@@ -0,0 +1,472 @@
+package informer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// eventsSyncTimeout is how long event lookups wait for the events of a cluster to be listed
+const eventsSyncTimeout = 15 * time.Second
+
+// KubernetesEvent is a core/v1 or events.k8s.io/v1 Event in a common form
+type KubernetesEvent struct {
+	UID            string         `json:"uid"`
+	Namespace      string         `json:"namespace"`
+	Name           string         `json:"name"`
+	Type           string         `json:"type"` // Normal, Warning
+	Reason         string         `json:"reason"`
+	Message        string         `json:"message"`
+	Count          int64          `json:"count"`
+	FirstTimestamp time.Time      `json:"firstTimestamp"`
+	LastTimestamp  time.Time      `json:"lastTimestamp"`
+	Source         string         `json:"source,omitempty"` // reporting controller or component
+	Regarding      EventRegarding `json:"regarding"`
+}
+
+// EventRegarding is the object an event is about
+type EventRegarding struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"`
+	FieldPath string `json:"fieldPath,omitempty"` // like spec.containers{app}
+}
+
+// KubernetesEventChange is a change of an event of a watched object
+type KubernetesEventChange struct {
+	Type  string          `json:"type"` // ADDED, MODIFIED, DELETED
+	Event KubernetesEvent `json:"event"`
+}
+
+// EventsWatch passes the event changes of an object to a handler until it is stopped
+type EventsWatch struct {
+	index   *eventsIndex
+	target  eventTarget
+	handler func(KubernetesEventChange)
+}
+
+// Stop stops passing event changes to the handler
+func (w *EventsWatch) Stop() {
+	w.index.mu.Lock()
+	defer w.index.mu.Unlock()
+
+	delete(w.index.watches, w)
+}
+
+// eventsIndex holds the events of a cluster, shared by all lookups and watches
+type eventsIndex struct {
+	informer  cache.SharedIndexInformer
+	watches   map[*EventsWatch]struct{}
+	lastError string
+	mu        sync.Mutex
+}
+
+// eventTarget is an object events are looked up for
+type eventTarget struct {
+	kind      string
+	namespace string
+	name      string
+	uid       types.UID
+}
+
+// matches reports whether an event is about the target. Events of an earlier
+// object with the same name are left out once the object is known, except for
+// cluster-scoped objects: the kubelet reports Node events with the node name as UID.
+func (t eventTarget) matches(event KubernetesEvent) bool {
+	if t.uid != "" && event.Regarding.UID == string(t.uid) {
+		return true
+	}
+	if event.Regarding.Kind != t.kind || event.Regarding.Namespace != t.namespace || event.Regarding.Name != t.name {
+		return false
+	}
+	return t.uid == "" || event.Regarding.UID == "" || t.namespace == ""
+}
+
+// GetEventsFor returns the events of an object, oldest first. The events of a
+// cluster are watched once, on the first lookup, and shared by all lookups. Where
+// listing events cluster-wide is forbidden the events of each namespace are
+// watched on their own.
+func (im *InformerManager) GetEventsFor(clusterID string, gvr schema.GroupVersionResource, namespace, name string) ([]KubernetesEvent, error) {
+	cluster, err := im.getCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := im.eventTarget(cluster, gvr, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	index, err := im.clusterEvents(cluster, target.namespace)
+	if err != nil {
+		return nil, err
+	}
+	return index.eventsFor(target), nil
+}
+
+// WatchEventsFor returns the events of an object like GetEventsFor and passes
+// their changes to the handler until the returned watch is stopped
+func (im *InformerManager) WatchEventsFor(clusterID string, gvr schema.GroupVersionResource, namespace, name string, handler func(KubernetesEventChange)) ([]KubernetesEvent, *EventsWatch, error) {
+	cluster, err := im.getCluster(clusterID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	target, err := im.eventTarget(cluster, gvr, namespace, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	index, err := im.clusterEvents(cluster, target.namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Changes made while the events are listed may arrive twice, never not at all
+	eventsWatch := &EventsWatch{index: index, target: target, handler: handler}
+	index.mu.Lock()
+	index.watches[eventsWatch] = struct{}{}
+	index.mu.Unlock()
+
+	return index.eventsFor(target), eventsWatch, nil
+}
+
+// eventTarget identifies an object by its kind and name, and by its UID if it exists
+func (im *InformerManager) eventTarget(cluster *ClusterConnection, gvr schema.GroupVersionResource, namespace, name string) (eventTarget, error) {
+	gvk, err := cluster.Mapper.KindFor(gvr)
+	if err != nil {
+		return eventTarget{}, fmt.Errorf("failed to resolve kind of %s: %w", gvr.String(), err)
+	}
+
+	target := eventTarget{kind: gvk.Kind, namespace: namespace, name: name}
+
+	// Events of deleted objects are still found by name
+	obj, err := im.getObject(context.TODO(), cluster, gvr, namespace, name)
+	if err == nil {
+		target.uid = obj.GetUID()
+	} else if !apierrors.IsNotFound(err) {
+		return eventTarget{}, err
+	}
+	return target, nil
+}
+
+// clusterEvents returns the events of a namespace, watching them on first use
+func (im *InformerManager) clusterEvents(cluster *ClusterConnection, namespace string) (*eventsIndex, error) {
+	// Events of cluster-scoped objects like nodes are reported in the default namespace
+	if namespace == metav1.NamespaceAll {
+		namespace = metav1.NamespaceDefault
+	}
+
+	cluster.mu.RLock()
+	index := cluster.eventsIndex(namespace)
+	cluster.mu.RUnlock()
+
+	if index == nil {
+		gvr, scope, err := im.eventsSource(cluster, namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		cluster.mu.Lock()
+		if index = cluster.eventsIndex(scope); index == nil {
+			if cluster.events == nil {
+				cluster.events = make(map[string]*eventsIndex)
+			}
+			index = im.startEventsIndex(cluster, gvr, scope)
+			cluster.events[scope] = index
+		}
+		cluster.mu.Unlock()
+	}
+
+	ctx, cancel := context.WithTimeout(cluster.ctx, eventsSyncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(ctx.Done(), index.informer.HasSynced) {
+		index.mu.Lock()
+		defer index.mu.Unlock()
+		if index.lastError != "" {
+			return nil, fmt.Errorf("failed to list events: %s", index.lastError)
+		}
+		return nil, fmt.Errorf("failed to list events in %s", eventsSyncTimeout)
+	}
+	return index, nil
+}
+
+// eventsIndex returns the running index holding the events of a namespace, if
+// any. The caller must hold the cluster lock.
+func (c *ClusterConnection) eventsIndex(namespace string) *eventsIndex {
+	if index := c.events[metav1.NamespaceAll]; index != nil {
+		return index
+	}
+	return c.events[namespace]
+}
+
+// eventsSource picks the events to watch for a namespace: events.k8s.io/v1 where
+// it is served, core/v1 otherwise, of all namespaces unless listing them
+// cluster-wide is forbidden. An API group the user may not list is skipped.
+func (im *InformerManager) eventsSource(cluster *ClusterConnection, namespace string) (schema.GroupVersionResource, string, error) {
+	var gvrs []schema.GroupVersionResource
+	if mapping, err := cluster.Mapper.RESTMapping(schema.GroupKind{Group: "events.k8s.io", Kind: "Event"}, "v1"); err == nil {
+		gvrs = append(gvrs, mapping.Resource)
+	}
+	gvrs = append(gvrs, schema.GroupVersionResource{Group: "", Version: "v1", Resource: "events"})
+
+	ctx, cancel := context.WithTimeout(cluster.ctx, eventsSyncTimeout)
+	defer cancel()
+
+	var lastErr error
+	for _, gvr := range gvrs {
+		for _, scope := range []string{metav1.NamespaceAll, namespace} {
+			_, err := cluster.Client.Resource(gvr).Namespace(scope).List(ctx, metav1.ListOptions{Limit: 1})
+			if err == nil {
+				return gvr, scope, nil
+			}
+			if !apierrors.IsForbidden(err) && !apierrors.IsNotFound(err) {
+				return schema.GroupVersionResource{}, "", fmt.Errorf("failed to list events: %w", err)
+			}
+			lastErr = err
+		}
+	}
+	return schema.GroupVersionResource{}, "", fmt.Errorf("failed to list events: %w", lastErr)
+}
+
+// startEventsIndex watches the events of a namespace, or of all namespaces, until
+// the cluster is removed
+func (im *InformerManager) startEventsIndex(cluster *ClusterConnection, gvr schema.GroupVersionResource, namespace string) *eventsIndex {
+	client := cluster.Client.Resource(gvr).Namespace(namespace)
+	listWatch := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			return client.List(ctx, options)
+		},
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			return client.Watch(ctx, options)
+		},
+	}
+
+	index := &eventsIndex{
+		informer: cache.NewSharedIndexInformer(listWatch, &unstructured.Unstructured{}, 0, im.indexersFor(gvr)),
+		watches:  make(map[*EventsWatch]struct{}),
+	}
+
+	index.informer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+		index.mu.Lock()
+		index.lastError = err.Error()
+		index.mu.Unlock()
+	})
+
+	// Events stay in the history after the cluster deleted them
+	record := func(obj any) {
+		if object, ok := obj.(*unstructured.Unstructured); ok {
+			im.recordEventHistory(cluster.ID, object)
+		}
+	}
+
+	index.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			record(obj)
+			index.notify("ADDED", obj)
+		},
+		UpdateFunc: func(_, newObj any) {
+			record(newObj)
+			index.notify("MODIFIED", newObj)
+		},
+		DeleteFunc: func(obj any) {
+			index.notify("DELETED", obj)
+		},
+	})
+
+	go index.informer.RunWithContext(cluster.ctx)
+	return index
+}
+
+// notify passes an event change to the watches of the object it is about
+func (index *eventsIndex) notify(changeType string, obj any) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	object, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	index.mu.Lock()
+	if len(index.watches) == 0 {
+		index.mu.Unlock()
+		return
+	}
+	event := newKubernetesEvent(object)
+	var watches []*EventsWatch
+	for eventsWatch := range index.watches {
+		if eventsWatch.target.matches(event) {
+			watches = append(watches, eventsWatch)
+		}
+	}
+	index.mu.Unlock()
+
+	for _, eventsWatch := range watches {
+		eventsWatch.handler(KubernetesEventChange{Type: changeType, Event: event})
+	}
+}
+
+// eventsFor returns the events of a target, oldest first
+func (index *eventsIndex) eventsFor(target eventTarget) []KubernetesEvent {
+	indexer := index.informer.GetIndexer()
+
+	var candidates []any
+	if target.uid != "" {
+		byUID, _ := indexer.ByIndex(IndexRegardingUID, string(target.uid))
+		candidates = append(candidates, byUID...)
+	}
+	byName, _ := indexer.ByIndex(IndexRegardingName, target.namespace+"/"+target.name)
+	candidates = append(candidates, byName...)
+
+	seen := make(map[types.UID]struct{})
+	events := []KubernetesEvent{}
+	for _, item := range candidates {
+		object, ok := item.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		if _, exists := seen[object.GetUID()]; exists {
+			continue
+		}
+		seen[object.GetUID()] = struct{}{}
+
+		if event := newKubernetesEvent(object); target.matches(event) {
+			events = append(events, event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].LastTimestamp.Equal(events[j].LastTimestamp) {
+			return events[i].LastTimestamp.Before(events[j].LastTimestamp)
+		}
+		return events[i].Name < events[j].Name
+	})
+	return events
+}
+
+// isEventsV1 reports whether an event is an events.k8s.io Event rather than a core/v1 one
+func isEventsV1(obj *unstructured.Unstructured) bool {
+	return obj.GroupVersionKind().Group == "events.k8s.io"
+}
+
+// eventRegarding returns the object an event is about
+func eventRegarding(obj *unstructured.Unstructured) EventRegarding {
+	field := "involvedObject"
+	if isEventsV1(obj) {
+		field = "regarding"
+	}
+
+	regarding, _, _ := unstructured.NestedStringMap(obj.Object, field)
+	return EventRegarding{
+		Kind:      regarding["kind"],
+		Namespace: regarding["namespace"],
+		Name:      regarding["name"],
+		UID:       regarding["uid"],
+		FieldPath: regarding["fieldPath"],
+	}
+}
+
+// newKubernetesEvent converts a core/v1 or events.k8s.io/v1 Event
+func newKubernetesEvent(obj *unstructured.Unstructured) KubernetesEvent {
+	event := KubernetesEvent{
+		UID:       string(obj.GetUID()),
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Regarding: eventRegarding(obj),
+	}
+	event.Type, _, _ = unstructured.NestedString(obj.Object, "type")
+	event.Reason, _, _ = unstructured.NestedString(obj.Object, "reason")
+
+	seriesCount, _, _ := unstructured.NestedInt64(obj.Object, "series", "count")
+	seriesLast := eventTime(obj, "series", "lastObservedTime")
+	eventTimestamp := eventTime(obj, "eventTime")
+
+	if isEventsV1(obj) {
+		event.Message, _, _ = unstructured.NestedString(obj.Object, "note")
+		event.Count, _, _ = unstructured.NestedInt64(obj.Object, "deprecatedCount")
+		event.FirstTimestamp = firstTime(eventTime(obj, "deprecatedFirstTimestamp"), eventTimestamp)
+		event.LastTimestamp = firstTime(seriesLast, eventTime(obj, "deprecatedLastTimestamp"), eventTimestamp)
+		event.Source, _, _ = unstructured.NestedString(obj.Object, "reportingController")
+		if event.Source == "" {
+			event.Source, _, _ = unstructured.NestedString(obj.Object, "deprecatedSource", "component")
+		}
+	} else {
+		event.Message, _, _ = unstructured.NestedString(obj.Object, "message")
+		event.Count, _, _ = unstructured.NestedInt64(obj.Object, "count")
+		event.FirstTimestamp = firstTime(eventTime(obj, "firstTimestamp"), eventTimestamp)
+		event.LastTimestamp = firstTime(seriesLast, eventTime(obj, "lastTimestamp"), eventTimestamp)
+		event.Source, _, _ = unstructured.NestedString(obj.Object, "source", "component")
+		if event.Source == "" {
+			event.Source, _, _ = unstructured.NestedString(obj.Object, "reportingComponent")
+		}
+	}
+
+	if seriesCount > event.Count {
+		event.Count = seriesCount
+	}
+	if event.Count == 0 {
+		event.Count = 1
+	}
+	if event.FirstTimestamp.IsZero() {
+		event.FirstTimestamp = obj.GetCreationTimestamp().Time
+	}
+	if event.LastTimestamp.IsZero() {
+		event.LastTimestamp = event.FirstTimestamp
+	}
+	return event
+}
+
+// eventTime parses a timestamp field of an event, zero if it is unset
+func eventTime(obj *unstructured.Unstructured, fields ...string) time.Time {
+	value, _, _ := unstructured.NestedString(obj.Object, fields...)
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
+
+// firstTime returns the first time that is set
+func firstTime(times ...time.Time) time.Time {
+	for _, t := range times {
+		if !t.IsZero() {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// indexRegardingUID indexes events by the UID of the object they are about
+func indexRegardingUID(obj any) ([]string, error) {
+	object, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+
+	if uid := eventRegarding(object).UID; uid != "" {
+		return []string{uid}, nil
+	}
+	return nil, nil
+}
+
+// indexRegardingName indexes events by the namespace/name of the object they are about
+func indexRegardingName(obj any) ([]string, error) {
+	object, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+
+	regarding := eventRegarding(object)
+	return []string{regarding.Namespace + "/" + regarding.Name}, nil
+}
@@ -0,0 +1,209 @@
+package informer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DefaultHealthCheckInterval is how often the connection of a healthy cluster is probed
+const DefaultHealthCheckInterval = 15 * time.Second
+
+const (
+	healthProbeTimeout  = 5 * time.Second
+	minReconnectBackoff = time.Second
+	maxReconnectBackoff = 2 * time.Minute
+)
+
+// SetHealthCheckInterval sets how often healthy clusters are probed
+func (im *InformerManager) SetHealthCheckInterval(interval time.Duration) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.healthCheckInterval = interval
+}
+
+// SetStatusHandler sets the handler called with the cluster ID whenever the
+// connection status of a cluster changes
+func (im *InformerManager) SetStatusHandler(handler func(clusterID string)) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.statusHandler = handler
+}
+
+// Health returns the connection status of the cluster and the error that caused it
+func (c *ClusterConnection) Health() (string, string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.Status, c.LastError
+}
+
+// monitorHealth probes the cluster until the context is done. An unhealthy cluster
+// is probed with exponential backoff, and once it recovers the watchers that were
+// running when it went down are restarted.
+func (im *InformerManager) monitorHealth(ctx context.Context, cluster *ClusterConnection) {
+	backoff := minReconnectBackoff
+	delay := im.getHealthCheckInterval()
+
+	// Informers running when the cluster went down, their watches are broken
+	var interrupted map[cache.SharedIndexInformer]struct{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		status, lastError := probeHealth(ctx, cluster.REST)
+		if ctx.Err() != nil {
+			return
+		}
+
+		previous := im.setClusterHealth(cluster, status, lastError)
+		if status != "connected" {
+			if previous == "connected" {
+				interrupted = cluster.runningInformers()
+			}
+			delay = backoff
+			backoff = min(backoff*2, maxReconnectBackoff)
+			continue
+		}
+
+		if previous != "connected" {
+			im.reconnectWatchers(cluster, interrupted)
+			interrupted = nil
+		}
+		delay = im.getHealthCheckInterval()
+		backoff = minReconnectBackoff
+	}
+}
+
+// getHealthCheckInterval returns the probe interval of healthy clusters
+func (im *InformerManager) getHealthCheckInterval() time.Duration {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	return im.healthCheckInterval
+}
+
+// probeHealth probes the readiness of the apiserver and returns the resulting cluster status:
+// connected, degraded when the server responds but is not ready, disconnected when it
+// cannot be reached, or error when the credentials are rejected
+func probeHealth(ctx context.Context, client rest.Interface) (string, string) {
+	ctx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+
+	_, err := client.Get().AbsPath("/readyz").DoRaw(ctx)
+	if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) {
+		// Not every user may read the readiness endpoint, the version endpoint is public
+		_, err = client.Get().AbsPath("/version").DoRaw(ctx)
+	}
+
+	var status apierrors.APIStatus
+	switch {
+	case err == nil:
+		return "connected", ""
+	case apierrors.IsUnauthorized(err):
+		return "error", fmt.Sprintf("authentication failed: %v", err)
+	case errors.As(err, &status):
+		return "degraded", err.Error()
+	default:
+		return "disconnected", err.Error()
+	}
+}
+
+// setClusterHealth records the probed status of a cluster, notifies the status
+// handler on a transition and returns the previous status
+func (im *InformerManager) setClusterHealth(cluster *ClusterConnection, status, lastError string) string {
+	cluster.mu.Lock()
+	previous := cluster.Status
+	cluster.Status = status
+	cluster.LastError = lastError
+	cluster.mu.Unlock()
+
+	if previous != status {
+		im.mu.RLock()
+		handler := im.statusHandler
+		im.mu.RUnlock()
+
+		if handler != nil {
+			handler(cluster.ID)
+		}
+	}
+
+	return previous
+}
+
+// runningInformers returns the informers of all watchers of a cluster
+func (c *ClusterConnection) runningInformers() map[cache.SharedIndexInformer]struct{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	informers := make(map[cache.SharedIndexInformer]struct{})
+	for _, watcher := range c.Informers {
+		for _, informer := range watcher.Informers() {
+			informers[informer] = struct{}{}
+		}
+	}
+	return informers
+}
+
+// reconnectWatchers restarts the informers of a cluster that were interrupted by an
+// outage or have not synced, so they watch again right away instead of waiting for
+// their own retry backoff. Restarted informers resume from the objects they held.
+func (im *InformerManager) reconnectWatchers(cluster *ClusterConnection, interrupted map[cache.SharedIndexInformer]struct{}) {
+	type staleInformer struct {
+		watcher   *ResourceWatcher
+		namespace string
+	}
+
+	cluster.mu.RLock()
+	var stale []staleInformer
+	for _, watcher := range cluster.Informers {
+		for namespace, informer := range watcher.Informers() {
+			if _, wasRunning := interrupted[informer]; wasRunning || !informer.HasSynced() {
+				stale = append(stale, staleInformer{watcher: watcher, namespace: namespace})
+			}
+		}
+	}
+	cluster.mu.RUnlock()
+
+	if len(stale) == 0 {
+		return
+	}
+
+	stopped := make([]cache.SharedIndexInformer, len(stale))
+	for i, s := range stale {
+		stopped[i] = s.watcher.stopInformer(s.namespace)
+	}
+
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+
+	for i, s := range stale {
+		if stopped[i] == nil {
+			continue // Stopped meanwhile
+		}
+		im.saveLastSyncResourceVersion(cluster.ID, s.watcher.Key, s.namespace, stopped[i])
+
+		// The watcher may have been removed or rescoped while it was stopped
+		key := s.watcher.Key
+		if s.watcher.Stopped() || cluster.Informers[key] != s.watcher {
+			continue
+		}
+		if s.namespace != "" && !slices.Contains(key.NamespaceList(), s.namespace) {
+			continue
+		}
+
+		im.resumeNamespaceInformer(cluster, s.watcher, s.namespace, stopped[i])
+	}
+}
@@ -0,0 +1,384 @@
+package informer
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Indexes of every informer, in addition to cache.NamespaceIndex
+const (
+	IndexOwnerUID = "ownerUID" // UIDs of the owner references
+	IndexNodeName = "nodeName" // spec.nodeName of pods
+	IndexLabelKey = "labelKey" // keys of the labels
+	IndexImage    = "image"    // container images of pods and pod templates
+	// IndexDependency holds kind/name of the config maps, secrets, persistent volume
+	// claims and service accounts pods and pod templates use, like ConfigMap/settings
+	IndexDependency = "dependency"
+)
+
+// Indexes of specific resource types
+const (
+	IndexServiceSelector = "serviceSelector" // key=value pairs of the selectors of services
+	IndexEndpointAddress = "endpointAddress" // endpoint addresses of endpoint slices
+	IndexBackendService  = "backendService"  // namespace/name of the services ingresses and HTTP routes send to
+	IndexRegardingUID    = "regardingUID"    // UID of the object core/v1 and events.k8s.io/v1 events are about
+	IndexRegardingName   = "regardingName"   // namespace/name of the object events are about
+)
+
+// Resource types with indexes of their own
+var (
+	servicesResource       = schema.GroupResource{Group: "", Resource: "services"}
+	endpointSlicesResource = schema.GroupResource{Group: "discovery.k8s.io", Resource: "endpointslices"}
+	ingressesResource      = schema.GroupResource{Group: "networking.k8s.io", Resource: "ingresses"}
+	httpRoutesResource     = schema.GroupResource{Group: "gateway.networking.k8s.io", Resource: "httproutes"}
+	coreEventsResource     = schema.GroupResource{Group: "", Resource: "events"}
+	eventsResource         = schema.GroupResource{Group: "events.k8s.io", Resource: "events"}
+)
+
+// resourceIndexers are the indexers added to the informers of specific resource types
+var resourceIndexers = map[schema.GroupResource]cache.Indexers{
+	servicesResource:       {IndexServiceSelector: indexServiceSelector},
+	endpointSlicesResource: {IndexEndpointAddress: indexEndpointAddress},
+	ingressesResource:      {IndexBackendService: indexIngressBackend},
+	httpRoutesResource:     {IndexBackendService: indexHTTPRouteBackend},
+	coreEventsResource:     {IndexRegardingUID: indexRegardingUID, IndexRegardingName: indexRegardingName},
+	eventsResource:         {IndexRegardingUID: indexRegardingUID, IndexRegardingName: indexRegardingName},
+}
+
+// podSpecPaths are the locations of pod specs in pods and workload resources
+var podSpecPaths = [][]string{
+	{"spec"},
+	{"spec", "template", "spec"},
+	{"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// defaultIndexers returns the indexers added to every informer
+func defaultIndexers() cache.Indexers {
+	return cache.Indexers{
+		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+		IndexOwnerUID:        indexOwnerUID,
+		IndexNodeName:        indexNodeName,
+		IndexLabelKey:        indexLabelKey,
+		IndexImage:           indexImage,
+		IndexDependency:      indexDependency,
+	}
+}
+
+// RegisterIndexer adds an index over the values a JSONPath expression, like
+// {.spec.serviceAccountName}, finds in the objects of a resource type. It applies
+// to running informers and to those started later.
+func (im *InformerManager) RegisterIndexer(gvr schema.GroupVersionResource, name, expression string) error {
+	_, isDefault := defaultIndexers()[name]
+	_, isResourceIndex := resourceIndexers[gvr.GroupResource()][name]
+	if isDefault || isResourceIndex || name == "" {
+		return fmt.Errorf("invalid indexer name %q", name)
+	}
+
+	indexFunc, err := newJSONPathIndexFunc(expression)
+	if err != nil {
+		return err
+	}
+
+	im.mu.Lock()
+	if _, exists := im.customIndexers[gvr][name]; exists {
+		im.mu.Unlock()
+		return fmt.Errorf("indexer %s already registered for %s", name, gvr.String())
+	}
+	if im.customIndexers[gvr] == nil {
+		im.customIndexers[gvr] = make(cache.Indexers)
+	}
+	im.customIndexers[gvr][name] = indexFunc
+
+	clusters := make([]*ClusterConnection, 0, len(im.clusters))
+	for _, cluster := range im.clusters {
+		clusters = append(clusters, cluster)
+	}
+	im.mu.Unlock()
+
+	// Informers index their existing objects when an indexer is added
+	for _, cluster := range clusters {
+		cluster.mu.RLock()
+		for key, watcher := range cluster.Informers {
+			if key.GVR != gvr {
+				continue
+			}
+			for _, informer := range watcher.Informers() {
+				if err := informer.AddIndexers(cache.Indexers{name: indexFunc}); err != nil {
+					fmt.Printf("Warning: Failed to add indexer %s to %s: %v\n", name, key.String(), err)
+				}
+			}
+		}
+		cluster.mu.RUnlock()
+	}
+
+	return nil
+}
+
+// indexersFor returns the indexers of a new informer for a resource type
+func (im *InformerManager) indexersFor(gvr schema.GroupVersionResource) cache.Indexers {
+	indexers := defaultIndexers()
+	for name, indexFunc := range resourceIndexers[gvr.GroupResource()] {
+		indexers[name] = indexFunc
+	}
+
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	for name, indexFunc := range im.customIndexers[gvr] {
+		indexers[name] = indexFunc
+	}
+	return indexers
+}
+
+// byIndex returns the objects of the watcher whose index contains the value
+func (w *ResourceWatcher) byIndex(indexName, value string) ([]any, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var result []any
+	for _, scoped := range w.informers {
+		objects, err := scoped.informer.GetIndexer().ByIndex(indexName, value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, objects...)
+	}
+	return result, nil
+}
+
+// indexOwnerUID indexes objects by the UIDs of their owners
+func indexOwnerUID(obj any) ([]string, error) {
+	object, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+
+	var uids []string
+	for _, owner := range object.GetOwnerReferences() {
+		uids = append(uids, string(owner.UID))
+	}
+	return uids, nil
+}
+
+// indexNodeName indexes pods by the node they are scheduled to
+func indexNodeName(obj any) ([]string, error) {
+	object, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+
+	nodeName, _, _ := unstructured.NestedString(object.Object, "spec", "nodeName")
+	if nodeName == "" {
+		return nil, nil
+	}
+	return []string{nodeName}, nil
+}
+
+// indexLabelKey indexes objects by the keys of their labels
+func indexLabelKey(obj any) ([]string, error) {
+	object, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+
+	var keys []string
+	for key := range object.GetLabels() {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// indexImage indexes pods and workloads by the images of their containers
+func indexImage(obj any) ([]string, error) {
+	object, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+
+	seen := make(map[string]struct{})
+	var images []string
+	for _, path := range podSpecPaths {
+		podSpec, found, _ := unstructured.NestedMap(object.Object, path...)
+		if !found {
+			continue
+		}
+
+		for _, field := range []string{"initContainers", "containers", "ephemeralContainers"} {
+			containers, _, _ := unstructured.NestedSlice(podSpec, field)
+			for _, container := range containers {
+				containerMap, ok := container.(map[string]any)
+				if !ok {
+					continue
+				}
+				image, _ := containerMap["image"].(string)
+				if _, exists := seen[image]; image != "" && !exists {
+					seen[image] = struct{}{}
+					images = append(images, image)
+				}
+			}
+		}
+	}
+	return images, nil
+}
+
+// indexServiceSelector indexes services by the key=value pairs of their selector
+func indexServiceSelector(obj any) ([]string, error) {
+	object, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+
+	selector, _, _ := unstructured.NestedStringMap(object.Object, "spec", "selector")
+	var pairs []string
+	for key, value := range selector {
+		pairs = append(pairs, key+"="+value)
+	}
+	return pairs, nil
+}
+
+// indexEndpointAddress indexes endpoint slices by the addresses of their endpoints
+func indexEndpointAddress(obj any) ([]string, error) {
+	object, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+
+	var addresses []string
+	endpoints, _, _ := unstructured.NestedSlice(object.Object, "endpoints")
+	for _, endpoint := range endpoints {
+		endpointMap, ok := endpoint.(map[string]any)
+		if !ok {
+			continue
+		}
+		endpointAddresses, _, _ := unstructured.NestedStringSlice(endpointMap, "addresses")
+		addresses = append(addresses, endpointAddresses...)
+	}
+	return addresses, nil
+}
+
+// indexIngressBackend indexes ingresses by the services of their backends
+func indexIngressBackend(obj any) ([]string, error) {
+	object, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+
+	seen := make(map[string]struct{})
+	var services []string
+	addBackend := func(backend map[string]any) {
+		name, _, _ := unstructured.NestedString(backend, "service", "name")
+		service := object.GetNamespace() + "/" + name
+		if _, exists := seen[service]; name != "" && !exists {
+			seen[service] = struct{}{}
+			services = append(services, service)
+		}
+	}
+
+	if backend, found, _ := unstructured.NestedMap(object.Object, "spec", "defaultBackend"); found {
+		addBackend(backend)
+	}
+	rules, _, _ := unstructured.NestedSlice(object.Object, "spec", "rules")
+	for _, rule := range rules {
+		ruleMap, ok := rule.(map[string]any)
+		if !ok {
+			continue
+		}
+		paths, _, _ := unstructured.NestedSlice(ruleMap, "http", "paths")
+		for _, path := range paths {
+			if pathMap, ok := path.(map[string]any); ok {
+				if backend, found, _ := unstructured.NestedMap(pathMap, "backend"); found {
+					addBackend(backend)
+				}
+			}
+		}
+	}
+	return services, nil
+}
+
+// indexHTTPRouteBackend indexes HTTP routes by the services of their backend references
+func indexHTTPRouteBackend(obj any) ([]string, error) {
+	object, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+
+	seen := make(map[string]struct{})
+	var services []string
+	rules, _, _ := unstructured.NestedSlice(object.Object, "spec", "rules")
+	for _, rule := range rules {
+		ruleMap, ok := rule.(map[string]any)
+		if !ok {
+			continue
+		}
+		backendRefs, _, _ := unstructured.NestedSlice(ruleMap, "backendRefs")
+		for _, backendRef := range backendRefs {
+			refMap, ok := backendRef.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			// Backends are services of the core group unless stated otherwise
+			group, _ := refMap["group"].(string)
+			kind, _ := refMap["kind"].(string)
+			if group != "" || (kind != "" && kind != "Service") {
+				continue
+			}
+
+			name, _ := refMap["name"].(string)
+			namespace, _ := refMap["namespace"].(string)
+			if namespace == "" {
+				namespace = object.GetNamespace()
+			}
+			service := namespace + "/" + name
+			if _, exists := seen[service]; name != "" && !exists {
+				seen[service] = struct{}{}
+				services = append(services, service)
+			}
+		}
+	}
+	return services, nil
+}
+
+// newJSONPathIndexFunc returns an index function over the values a JSONPath expression finds
+func newJSONPathIndexFunc(expression string) (cache.IndexFunc, error) {
+	if !strings.HasPrefix(expression, "{") {
+		expression = "{" + expression + "}"
+	}
+
+	parser := jsonpath.New("indexer").AllowMissingKeys(true)
+	if err := parser.Parse(expression); err != nil {
+		return nil, fmt.Errorf("invalid JSONPath expression %q: %w", expression, err)
+	}
+
+	// A parsed expression keeps state while it is evaluated
+	var mu sync.Mutex
+	return func(obj any) ([]string, error) {
+		object, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil, nil
+		}
+
+		mu.Lock()
+		results, err := parser.FindResults(object.Object)
+		mu.Unlock()
+		if err != nil {
+			return nil, nil // Objects without the field are not indexed
+		}
+
+		var values []string
+		for _, result := range results {
+			for _, value := range result {
+				if value.IsValid() && value.CanInterface() {
+					values = append(values, fmt.Sprint(value.Interface()))
+				}
+			}
+		}
+		return values, nil
+	}, nil
+}
@@ -0,0 +1,159 @@
+package informer
+
+import (
+	"context"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/metadata"
+)
+
+// resourceClient lists and watches the objects of a resource as unstructured objects
+type resourceClient interface {
+	List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+}
+
+// resumableListWatch is a ListerWatcher that answers the first list from
+// previously cached objects, so the reflector starts its watch at a persisted
+// resourceVersion instead of listing everything from the apiserver.
+//
+// If the persisted resourceVersion has been compacted away, the watch fails
+// with 410 Gone and the reflector relists; every list after the first one
+// goes to the apiserver.
+type resumableListWatch struct {
+	client           resourceClient
+	tweakListOptions func(*metav1.ListOptions)
+	seed             []*unstructured.Unstructured
+	resourceVersion  string
+	onList           func(resourceVersion string)
+	onWatch          func() // called when a watch was established
+	mu               sync.Mutex
+}
+
+// newResumableListWatch creates a list watch for the given resource client.
+// An empty resourceVersion disables priming and always lists from the server.
+func newResumableListWatch(client resourceClient, tweakListOptions func(*metav1.ListOptions), seed []*unstructured.Unstructured, resourceVersion string, onList func(string), onWatch func()) *resumableListWatch {
+	return &resumableListWatch{
+		client:           client,
+		tweakListOptions: tweakListOptions,
+		seed:             seed,
+		resourceVersion:  resourceVersion,
+		onList:           onList,
+		onWatch:          onWatch,
+	}
+}
+
+// List returns the cached seed on the first call and lists from the server afterwards
+func (lw *resumableListWatch) List(options metav1.ListOptions) (runtime.Object, error) {
+	lw.mu.Lock()
+	seed, resourceVersion := lw.seed, lw.resourceVersion
+	lw.seed, lw.resourceVersion = nil, ""
+	lw.mu.Unlock()
+
+	if resourceVersion != "" {
+		list := &unstructured.UnstructuredList{
+			Items: make([]unstructured.Unstructured, 0, len(seed)),
+		}
+		for _, obj := range seed {
+			list.Items = append(list.Items, *obj)
+		}
+		list.SetResourceVersion(resourceVersion)
+		return list, nil
+	}
+
+	if lw.tweakListOptions != nil {
+		lw.tweakListOptions(&options)
+	}
+
+	list, err := lw.client.List(context.TODO(), options)
+	if err != nil {
+		return nil, err
+	}
+
+	if lw.onList != nil && list.GetResourceVersion() != "" {
+		lw.onList(list.GetResourceVersion())
+	}
+
+	return list, nil
+}
+
+// Watch starts a watch against the server
+func (lw *resumableListWatch) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	if lw.tweakListOptions != nil {
+		lw.tweakListOptions(&options)
+	}
+
+	w, err := lw.client.Watch(context.TODO(), options)
+	if err == nil && lw.onWatch != nil {
+		lw.onWatch()
+	}
+	return w, err
+}
+
+// metadataClient is a resourceClient that only transfers object metadata.
+// Objects are converted to unstructured objects holding the metadata, so
+// they are handled like full objects.
+type metadataClient struct {
+	client metadata.ResourceInterface
+}
+
+// List lists object metadata
+func (c metadataClient) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	list, err := c.client.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &unstructured.UnstructuredList{
+		Items: make([]unstructured.Unstructured, 0, len(list.Items)),
+	}
+	result.SetResourceVersion(list.ResourceVersion)
+	result.SetContinue(list.Continue)
+	if list.RemainingItemCount != nil {
+		result.SetRemainingItemCount(list.RemainingItemCount)
+	}
+
+	for i := range list.Items {
+		obj, err := metadataToUnstructured(&list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, *obj)
+	}
+	return result, nil
+}
+
+// Watch watches object metadata
+func (c metadataClient) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	w, err := c.client.Watch(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return watch.Filter(w, func(event watch.Event) (watch.Event, bool) {
+		partial, ok := event.Object.(*metav1.PartialObjectMetadata)
+		if !ok {
+			return event, true // Errors are passed on as is
+		}
+
+		obj, err := metadataToUnstructured(partial)
+		if err != nil {
+			return event, false
+		}
+		event.Object = obj
+		return event, true
+	}), nil
+}
+
+// metadataToUnstructured converts object metadata to an unstructured object
+func metadataToUnstructured(obj *metav1.PartialObjectMetadata) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
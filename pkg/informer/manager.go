@@ -9,35 +9,67 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/tidwall/sjson"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 
 	_ "modernc.org/sqlite"
 )
 
+// DefaultResyncPeriod is the informer resync period used unless configured otherwise
+const DefaultResyncPeriod = 30 * time.Second
+
 // ClusterConnection represents a Kubernetes cluster connection
 type ClusterConnection struct {
-	ID        string                                                    `json:"id"`
-	Name      string                                                    `json:"name"`
-	Config    *rest.Config                                              `json:"-"`
-	Client    dynamic.Interface                                         `json:"-"`
-	Factory   dynamicinformer.DynamicSharedInformerFactory              `json:"-"`
-	Informers map[schema.GroupVersionResource]cache.SharedIndexInformer `json:"-"`
-	Context   string                                                    `json:"context"`
-	Server    string                                                    `json:"server"`
-	Status    string                                                    `json:"status"` // connected, disconnected, error
-	LastError string                                                    `json:"lastError,omitempty"`
-	IsPinned  bool                                                      `json:"isPinned"`
-	mu        sync.RWMutex
+	ID        string                             `json:"id"`
+	Name      string                             `json:"name"`
+	Config    *rest.Config                       `json:"-"`
+	Client    dynamic.Interface                  `json:"-"`
+	Metadata  metadata.Interface                 `json:"-"`
+	REST      rest.Interface                     `json:"-"`
+	Discovery discovery.CachedDiscoveryInterface `json:"-"`
+	Mapper    meta.RESTMapper                    `json:"-"`
+	Informers map[WatcherKey]*ResourceWatcher    `json:"-"`
+	Context   string                             `json:"context"`
+	Server    string                             `json:"server"`
+	Status    string                             `json:"status"` // connected, degraded, disconnected, error
+	LastError string                             `json:"lastError,omitempty"`
+	IsPinned  bool                               `json:"isPinned"`
+
+	// Resync periods apply to informers started after they are set
+	ResyncPeriod          time.Duration                                 `json:"resyncPeriod"`
+	ResourceResyncPeriods map[schema.GroupVersionResource]time.Duration `json:"-"`
+
+	// SuppressedEvents counts resync updates dropped because nothing changed
+	SuppressedEvents atomic.Uint64 `json:"-"`
+
+	// Discovered resource types, dropped when CRDs or APIServices change
+	apiResources *APIResources
+	discoveryMu  sync.Mutex
+
+	// Resolved root controllers, updated when their owners change
+	rootControllers *rootControllerCache
+
+	// Events by namespace, watched on the first event lookup. The events of all
+	// namespaces are held under "" unless listing them cluster-wide is forbidden.
+	events map[string]*eventsIndex
+
+	ctx    context.Context    // done when the cluster is removed
+	cancel context.CancelFunc // stops the health monitor, the resource type watch and the events
+	mu     sync.RWMutex
 }
 
 // ResourceVersionStore manages persistent storage of resource versions
@@ -88,6 +120,26 @@ type InformerManager struct {
 	mu           sync.RWMutex
 	ctx          context.Context
 	cancel       context.CancelFunc
+
+	// Cluster health monitoring
+	healthCheckInterval time.Duration
+	statusHandler       func(clusterID string)
+	watchErrorHandler   func(WatchError)
+
+	// Discovery
+	discoveryCacheDir    string
+	resourceTypesHandler func(clusterID string)
+
+	// Indexers registered per resource type, in addition to the default ones
+	customIndexers map[schema.GroupVersionResource]cache.Indexers
+
+	// Root controller resolution
+	excludedOwnerKinds    map[string]bool
+	rootControllerHandler func(clusterID string, results []RootControllerResult)
+
+	// Watched events are kept in the database cache for this long
+	eventRetention EventRetention
+	eventHistory   chan recordedEvent // written by one goroutine in batches
 }
 
 // DefaultSensitiveConfig is the default configuration for sensitive resources
@@ -115,7 +167,9 @@ var DefaultSensitiveConfig = &SensitiveResourceConfig{
 	},
 }
 
-// Event represents a resource event from informers
+// Event represents a resource event from informers. Each watcher reports the objects
+// of its scope, DELETED events of selector-scoped watchers are also sent for objects
+// that stopped matching the selectors.
 type Event struct {
 	Type      string                      `json:"type"` // ADDED, MODIFIED, DELETED
 	ClusterID string                      `json:"clusterId"`
@@ -125,6 +179,12 @@ type Event struct {
 	Object    *unstructured.Unstructured  `json:"object"`
 	OldObject *unstructured.Unstructured  `json:"oldObject,omitempty"`
 	Timestamp time.Time                   `json:"timestamp"`
+
+	// MetadataOnly is set when the objects only carry metadata, see WatcherKey
+	MetadataOnly bool `json:"metadataOnly,omitempty"`
+	// Watcher is the watcher that reported the event, watchers of overlapping scopes
+	// report the same changes
+	Watcher WatcherKey `json:"watcher"`
 }
 
 // NewInformerManager creates a new informer manager
@@ -147,21 +207,31 @@ func NewInformerManager(storePath string, eventHandler func(Event)) *InformerMan
 		fmt.Printf("Warning: Failed to initialize database cache: %v\n", err)
 	}
 
-	return &InformerManager{
+	im := &InformerManager{
 		clusters:     make(map[string]*ClusterConnection),
 		store:        store,
 		dbCache:      dbCache,
 		eventHandler: eventHandler,
 		ctx:          ctx,
 		cancel:       cancel,
+
+		healthCheckInterval: DefaultHealthCheckInterval,
+		discoveryCacheDir:   filepath.Join(cacheDir, "discovery"),
+		customIndexers:      make(map[schema.GroupVersionResource]cache.Indexers),
+		eventRetention:      DefaultEventRetention,
+		eventHistory:        make(chan recordedEvent, eventHistoryQueueSize),
 	}
+
+	if dbCache != nil {
+		go im.writeEventHistory()
+		go im.pruneEventHistory()
+	}
+
+	return im
 }
 
 // AddCluster adds a new cluster connection
 func (im *InformerManager) AddCluster(id, name, kubeconfig, context string) error {
-	im.mu.Lock()
-	defer im.mu.Unlock()
-
 	// Parse kubeconfig
 	config, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeconfig))
 	if err != nil {
@@ -178,21 +248,55 @@ func (im *InformerManager) AddCluster(id, name, kubeconfig, context string) erro
 		return fmt.Errorf("failed to create dynamic client: %w", err)
 	}
 
-	// Create informer factory
-	factory := dynamicinformer.NewDynamicSharedInformerFactory(client, 30*time.Second)
+	// Create metadata client for metadata-only watchers
+	metadataClient, err := metadata.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create metadata client: %w", err)
+	}
+
+	// Create REST client for requests with custom content types, like tables
+	restConfig := rest.CopyConfig(config)
+	restConfig.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+	restClient, err := rest.UnversionedRESTClientFor(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create REST client: %w", err)
+	}
+
+	// Create discovery client cached on disk, refreshed when CRDs or APIServices change
+	discoveryClient, err := newCachedDiscoveryClient(config, im.discoveryCacheDir)
+	if err != nil {
+		return fmt.Errorf("failed to create discovery client: %w", err)
+	}
 
 	cluster := &ClusterConnection{
 		ID:        id,
 		Name:      name,
 		Config:    config,
 		Client:    client,
-		Factory:   factory,
-		Informers: make(map[schema.GroupVersionResource]cache.SharedIndexInformer),
+		Metadata:  metadataClient,
+		REST:      restClient,
+		Discovery: discoveryClient,
+		Mapper:    restmapper.NewShortcutExpander(restmapper.NewDeferredDiscoveryRESTMapper(discoveryClient), discoveryClient, nil),
+		Informers: make(map[WatcherKey]*ResourceWatcher),
 		Context:   context,
 		Server:    config.Host,
-		Status:    "connected",
+
+		ResyncPeriod:          DefaultResyncPeriod,
+		ResourceResyncPeriods: make(map[schema.GroupVersionResource]time.Duration),
+
+		rootControllers: newRootControllerCache(),
 	}
 
+	// Contact the server once so the initial status is known
+	cluster.Status, cluster.LastError = probeHealth(im.ctx, restClient)
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	// A replaced connection stops probing
+	if previous, exists := im.clusters[id]; exists && previous.cancel != nil {
+		previous.cancel()
+	}
 	im.clusters[id] = cluster
 
 	// Initialize store for this cluster if not exists
@@ -200,43 +304,58 @@ func (im *InformerManager) AddCluster(id, name, kubeconfig, context string) erro
 		im.store.data[id] = make(map[string]string)
 	}
 
+	im.startClusterMonitors(cluster)
+
 	return nil
 }
 
+// startClusterMonitors starts the health monitor and the resource type watch of
+// a cluster, they stop when the cluster is removed
+func (im *InformerManager) startClusterMonitors(cluster *ClusterConnection) {
+	ctx, cancel := context.WithCancel(im.ctx)
+	cluster.ctx, cluster.cancel = ctx, cancel
+
+	go im.monitorHealth(ctx, cluster)
+	go im.watchResourceTypes(ctx, cluster)
+}
+
 // RemoveCluster removes a cluster connection and stops all its informers
 func (im *InformerManager) RemoveCluster(id string) error {
 	im.mu.Lock()
-	defer im.mu.Unlock()
-
 	cluster, exists := im.clusters[id]
 	if !exists {
+		im.mu.Unlock()
 		return fmt.Errorf("cluster %s not found", id)
 	}
 
-	cluster.mu.Lock()
-	defer cluster.mu.Unlock()
-
-	// Stop all informers for this cluster
-	for gvr := range cluster.Informers {
-		// Stop the informer (this will be handled by factory stop)
-		delete(cluster.Informers, gvr)
-	}
-
-	// Stop the factory
-	cluster.Factory.Shutdown()
-
 	// Remove from clusters map
 	delete(im.clusters, id)
 
 	// Clean up store data for this cluster
 	delete(im.store.data, id)
 	im.store.save()
+	im.mu.Unlock()
+
+	cluster.cancel()
+
+	cluster.mu.Lock()
+	watchers := make([]*ResourceWatcher, 0, len(cluster.Informers))
+	for gvr, watcher := range cluster.Informers {
+		watchers = append(watchers, watcher)
+		delete(cluster.Informers, gvr)
+	}
+	cluster.mu.Unlock()
+
+	// Stop all informers for this cluster
+	for _, watcher := range watchers {
+		watcher.Stop()
+	}
 
 	return nil
 }
 
-// AddResourceWatcher adds a watcher for a specific GVK
-func (im *InformerManager) AddResourceWatcher(clusterID string, gvr schema.GroupVersionResource, namespace string) error {
+// AddResourceWatcher adds a watcher for a specific GVR and namespace set
+func (im *InformerManager) AddResourceWatcher(clusterID string, key WatcherKey) error {
 	im.mu.RLock()
 	cluster, exists := im.clusters[clusterID]
 	im.mu.RUnlock()
@@ -248,89 +367,413 @@ func (im *InformerManager) AddResourceWatcher(clusterID string, gvr schema.Group
 	cluster.mu.Lock()
 	defer cluster.mu.Unlock()
 
-	// Check if informer already exists
-	if _, exists := cluster.Informers[gvr]; exists {
+	// Check if watcher already exists
+	if _, exists := cluster.Informers[key]; exists {
 		return nil // Already watching
 	}
 
-	// Get last known resource version for this GVR (for future use)
-	// lastResourceVersion := im.store.getResourceVersion(clusterID, gvr.String())
+	namespaces := key.NamespaceList()
+	if namespaces == nil {
+		namespaces = []string{metav1.NamespaceAll}
+	}
 
-	// Test API access first to handle 401 errors
-	if err := im.testAPIAccess(cluster, gvr, namespace); err != nil {
-		cluster.Status = "error"
-		if strings.Contains(err.Error(), "401") || strings.Contains(err.Error(), "Unauthorized") {
-			cluster.LastError = fmt.Sprintf("Unauthorized access to %s: %v", gvr.String(), err)
-			return fmt.Errorf("unauthorized access to %s in cluster %s: %w", gvr.String(), clusterID, err)
+	// Test API access first to handle 401, namespace-only RBAC and selector errors
+	for _, namespace := range namespaces {
+		if err := im.checkAPIAccess(cluster, key, namespace); err != nil {
+			return err
 		}
-		cluster.LastError = fmt.Sprintf("Failed to access %s: %v", gvr.String(), err)
+	}
+
+	watcher := newResourceWatcher(im.ctx, key)
+	for _, namespace := range namespaces {
+		im.startNamespaceInformer(cluster, watcher, namespace)
+	}
+
+	cluster.Informers[key] = watcher
+
+	return nil
+}
+
+// RemoveResourceWatcher removes a watcher and closes its watch connections
+func (im *InformerManager) RemoveResourceWatcher(clusterID string, key WatcherKey) error {
+	stop, err := im.DetachResourceWatcher(clusterID, key)
+	if err != nil {
 		return err
 	}
 
-	// Create and configure informer
-	informer := cluster.Factory.ForResource(gvr).Informer()
+	stop()
+	return nil
+}
 
-	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: func(obj any) {
-			im.handleEvent("ADDED", clusterID, gvr, obj, nil)
-		},
-		UpdateFunc: func(oldObj, newObj any) {
-			im.handleEvent("MODIFIED", clusterID, gvr, newObj, oldObj)
-		},
-		DeleteFunc: func(obj any) {
-			im.handleEvent("DELETED", clusterID, gvr, obj, nil)
-		},
-	})
+// DetachResourceWatcher removes a watcher right away, so that adding the key again
+// starts a new watcher, and returns the function that closes its watch connections
+// and waits for them. Callers holding locks can wait after releasing them.
+func (im *InformerManager) DetachResourceWatcher(clusterID string, key WatcherKey) (func(), error) {
+	im.mu.RLock()
+	cluster, exists := im.clusters[clusterID]
+	im.mu.RUnlock()
 
-	// Store the informer
-	cluster.Informers[gvr] = informer
+	if !exists {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
 
-	// Start the informer if not already started
-	go cluster.Factory.Start(im.ctx.Done())
+	cluster.mu.Lock()
+	watcher, exists := cluster.Informers[key]
+	if !exists {
+		cluster.mu.Unlock()
+		return nil, fmt.Errorf("watcher for %s not found", key.String())
+	}
+	delete(cluster.Informers, key)
+	cluster.mu.Unlock()
 
-	// Wait for cache sync with timeout in a separate goroutine
-	go func() {
-		ctx, cancel := context.WithTimeout(im.ctx, 30*time.Second)
-		defer cancel()
+	cluster.pruneRootControllers()
 
-		if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
-			cluster.mu.Lock()
-			cluster.Status = "error"
-			cluster.LastError = "failed to sync cache for " + gvr.String()
-			cluster.mu.Unlock()
-		}
-	}()
+	return func() {
+		// Close the watch connections, the informer stores are released with the watcher
+		watcher.Stop()
 
-	return nil
+		// Remember where the watches stopped so re-adding them can resume from there
+		for namespace, informer := range watcher.Informers() {
+			im.saveLastSyncResourceVersion(clusterID, key, namespace, informer)
+		}
+	}, nil
 }
 
-// RemoveResourceWatcher removes a watcher for a specific GVK
-func (im *InformerManager) RemoveResourceWatcher(clusterID string, gvr schema.GroupVersionResource) error {
+// AddWatcherNamespace adds a namespace to a namespace-scoped watcher and returns its new key
+func (im *InformerManager) AddWatcherNamespace(clusterID string, key WatcherKey, namespace string) (WatcherKey, error) {
 	im.mu.RLock()
 	cluster, exists := im.clusters[clusterID]
 	im.mu.RUnlock()
 
 	if !exists {
-		return fmt.Errorf("cluster %s not found", clusterID)
+		return key, fmt.Errorf("cluster %s not found", clusterID)
 	}
 
 	cluster.mu.Lock()
 	defer cluster.mu.Unlock()
 
-	// Check if informer exists
-	if _, exists := cluster.Informers[gvr]; !exists {
-		return fmt.Errorf("watcher for %s not found", gvr.String())
+	watcher, exists := cluster.Informers[key]
+	if !exists {
+		return key, fmt.Errorf("watcher for %s not found", key.String())
+	}
+
+	newKey, err := key.AddNamespace(namespace)
+	if err != nil {
+		return key, err
+	}
+	if newKey == key {
+		return key, nil // Already watching the namespace
+	}
+
+	if _, exists := cluster.Informers[newKey]; exists {
+		return key, fmt.Errorf("watcher for %s already exists", newKey.String())
+	}
+
+	if err := im.checkAPIAccess(cluster, key, namespace); err != nil {
+		return key, err
+	}
+
+	im.startNamespaceInformer(cluster, watcher, namespace)
+
+	watcher.setKey(newKey)
+	delete(cluster.Informers, key)
+	cluster.Informers[newKey] = watcher
+
+	return newKey, nil
+}
+
+// RemoveWatcherNamespace removes a namespace from a namespace-scoped watcher and returns its new key
+func (im *InformerManager) RemoveWatcherNamespace(clusterID string, key WatcherKey, namespace string) (WatcherKey, error) {
+	im.mu.RLock()
+	cluster, exists := im.clusters[clusterID]
+	im.mu.RUnlock()
+
+	if !exists {
+		return key, fmt.Errorf("cluster %s not found", clusterID)
+	}
+
+	cluster.mu.Lock()
+	watcher, exists := cluster.Informers[key]
+	if !exists {
+		cluster.mu.Unlock()
+		return key, fmt.Errorf("watcher for %s not found", key.String())
 	}
 
-	// Remove the informer
-	delete(cluster.Informers, gvr)
+	newKey, err := key.RemoveNamespace(namespace)
+	if err != nil {
+		cluster.mu.Unlock()
+		return key, err
+	}
+
+	if _, exists := cluster.Informers[newKey]; exists {
+		cluster.mu.Unlock()
+		return key, fmt.Errorf("watcher for %s already exists", newKey.String())
+	}
 
-	// Note: We can't actually stop individual informers in the factory,
-	// but removing from our map will stop event handling
+	watcher.setKey(newKey)
+	delete(cluster.Informers, key)
+	cluster.Informers[newKey] = watcher
+	cluster.mu.Unlock()
 
+	if informer := watcher.stopInformer(namespace); informer != nil {
+		im.saveLastSyncResourceVersion(clusterID, key, namespace, informer)
+	}
+	cluster.pruneRootControllers()
+
+	return newKey, nil
+}
+
+// checkAPIAccess tests API access of a watcher for a namespace. Failures are
+// reported for the watcher only, the other watchers of the cluster keep working.
+func (im *InformerManager) checkAPIAccess(cluster *ClusterConnection, key WatcherKey, namespace string) error {
+	err := im.testAPIAccess(cluster, key, namespace)
+	if err == nil {
+		return nil
+	}
+	im.reportAccessError(cluster.ID, key, namespace, err)
+
+	gvr := key.GVR
+	if strings.Contains(err.Error(), "401") || strings.Contains(err.Error(), "Unauthorized") {
+		return fmt.Errorf("unauthorized access to %s in cluster %s: %w", gvr.String(), cluster.ID, err)
+	}
+	return fmt.Errorf("failed to access %s in cluster %s: %w", gvr.String(), cluster.ID, err)
+}
+
+// startNamespaceInformer starts the informer for one namespace of a watcher, "" for all namespaces
+func (im *InformerManager) startNamespaceInformer(cluster *ClusterConnection, watcher *ResourceWatcher, namespace string) {
+	im.resumeNamespaceInformer(cluster, watcher, namespace, nil)
+}
+
+// resumeNamespaceInformer starts the informer for one namespace of a watcher in
+// place of a stopped one. It resumes from the store and resource version of the
+// stopped informer, whose objects the handlers have already seen.
+func (im *InformerManager) resumeNamespaceInformer(cluster *ClusterConnection, watcher *ResourceWatcher, namespace string, previous cache.SharedIndexInformer) {
+	clusterID := cluster.ID
+	key := watcher.Key
+	gvr := key.GVR
+	versionKey := resourceVersionKey(key, namespace)
+
+	watcher.startInformer(namespace, func(ctx context.Context) cache.SharedIndexInformer {
+		// Resume from the last known resource version, priming the informer
+		// store from the stopped informer or the database cache so the
+		// apiserver is not relisted
+		var seed []*unstructured.Unstructured
+		lastResourceVersion := ""
+		resumed := previous != nil && previous.LastSyncResourceVersion() != ""
+		if resumed {
+			lastResourceVersion = previous.LastSyncResourceVersion()
+			seed = storeObjects(previous)
+		} else {
+			lastResourceVersion = im.store.getResourceVersion(clusterID, versionKey)
+			seed = im.loadSeedResources(clusterID, key, namespace, lastResourceVersion)
+			if seed == nil {
+				lastResourceVersion = ""
+			}
+		}
+
+		var client resourceClient = cluster.Client.Resource(gvr).Namespace(namespace)
+		if key.MetadataOnly {
+			client = metadataClient{client: cluster.Metadata.Resource(gvr).Namespace(namespace)}
+		}
+
+		var informer cache.SharedIndexInformer
+		// Errors are cleared once the informer lists or watches again
+		listWatch := newResumableListWatch(client, key.tweakListOptions, seed, lastResourceVersion, func(resourceVersion string) {
+			im.store.setResourceVersion(clusterID, versionKey, resourceVersion)
+			watcher.clearError(namespace, informer)
+		}, func() {
+			watcher.clearError(namespace, informer)
+		})
+
+		// Create and configure informer
+		informer = cache.NewSharedIndexInformer(listWatch, &unstructured.Unstructured{}, cluster.resyncPeriod(gvr), im.indexersFor(gvr))
+
+		// The reflector retries on its own, failures are recorded so they can be shown
+		informer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+			if ctx.Err() != nil {
+				return
+			}
+			im.recordWatchError(clusterID, watcher, namespace, informer, classifyWatchError(err), err)
+		})
+
+		// Objects of the initial list arrive unordered, their resource versions
+		// are not recorded since the list watch records the list resource version
+		recordVersion := func(obj any) {
+			if object, ok := obj.(metav1.Object); ok && object.GetResourceVersion() != "" {
+				im.store.setResourceVersion(clusterID, versionKey, object.GetResourceVersion())
+			}
+		}
+
+		forward := func(eventType string, obj, oldObj any) {
+			watcher.clearError(namespace, informer)
+			if im.handleEvent(eventType, clusterID, key, obj, oldObj) {
+				watcher.recordEvent(obj)
+			}
+		}
+
+		// Events still queued when the informer is stopped are dropped
+		informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+			AddFunc: func(obj any, isInInitialList bool) {
+				if ctx.Err() != nil {
+					return
+				}
+				// The seed of a resumed informer was passed on by the informer it replaces
+				if resumed && isInInitialList {
+					return
+				}
+				if !isInInitialList {
+					recordVersion(obj)
+				}
+				forward("ADDED", obj, nil)
+			},
+			UpdateFunc: func(oldObj, newObj any) {
+				if ctx.Err() != nil {
+					return
+				}
+				// Resyncs replay unchanged objects, the version store is not rewritten for them
+				if !unchangedResync(oldObj, newObj) {
+					recordVersion(newObj)
+				}
+				forward("MODIFIED", newObj, oldObj)
+			},
+			DeleteFunc: func(obj any) {
+				if ctx.Err() != nil {
+					return
+				}
+				recordVersion(obj)
+				forward("DELETED", obj, nil)
+			},
+		})
+
+		// Wait for cache sync with timeout in a separate goroutine
+		go func() {
+			syncCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			defer cancel()
+
+			if !cache.WaitForCacheSync(syncCtx.Done(), informer.HasSynced) {
+				if ctx.Err() != nil {
+					return // Stopped before it synced
+				}
+				if watcher.hasError(namespace) {
+					return // The cause is already recorded
+				}
+
+				im.recordWatchError(clusterID, watcher, namespace, informer, "timeout", fmt.Errorf("failed to sync cache for %s", gvr.String()))
+			}
+		}()
+
+		return informer
+	})
+}
+
+// SetResyncPeriod sets the resync period of a cluster, zero disables resyncs.
+// Watchers already running keep their period until they are restarted.
+func (im *InformerManager) SetResyncPeriod(clusterID string, period time.Duration) error {
+	cluster, err := im.getCluster(clusterID)
+	if err != nil {
+		return err
+	}
+
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+
+	cluster.ResyncPeriod = period
+	return nil
+}
+
+// SetResourceResyncPeriod overrides the resync period of a cluster for one resource
+// type, a negative period removes the override
+func (im *InformerManager) SetResourceResyncPeriod(clusterID string, gvr schema.GroupVersionResource, period time.Duration) error {
+	cluster, err := im.getCluster(clusterID)
+	if err != nil {
+		return err
+	}
+
+	cluster.mu.Lock()
+	defer cluster.mu.Unlock()
+
+	if period < 0 {
+		delete(cluster.ResourceResyncPeriods, gvr)
+	} else {
+		cluster.ResourceResyncPeriods[gvr] = period
+	}
 	return nil
 }
 
+// getCluster returns a cluster by ID
+func (im *InformerManager) getCluster(clusterID string) (*ClusterConnection, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	cluster, exists := im.clusters[clusterID]
+	if !exists {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+	return cluster, nil
+}
+
+// resyncPeriod returns the resync period for a resource type.
+// The caller must hold the cluster lock.
+func (c *ClusterConnection) resyncPeriod(gvr schema.GroupVersionResource) time.Duration {
+	if period, exists := c.ResourceResyncPeriods[gvr]; exists {
+		return period
+	}
+	return c.ResyncPeriod
+}
+
+// storeObjects returns the objects held by the store of an informer
+func storeObjects(informer cache.SharedIndexInformer) []*unstructured.Unstructured {
+	items := informer.GetStore().List()
+	objects := make([]*unstructured.Unstructured, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(*unstructured.Unstructured); ok {
+			objects = append(objects, obj)
+		}
+	}
+	return objects
+}
+
+// saveLastSyncResourceVersion records where a stopped informer left off
+func (im *InformerManager) saveLastSyncResourceVersion(clusterID string, key WatcherKey, namespace string, informer cache.SharedIndexInformer) {
+	if resourceVersion := informer.LastSyncResourceVersion(); resourceVersion != "" {
+		im.store.setResourceVersion(clusterID, resourceVersionKey(key, namespace), resourceVersion)
+	}
+}
+
+// resourceVersionKey returns the version store key of a watcher informer for a namespace, "" for all namespaces
+func resourceVersionKey(key WatcherKey, namespace string) string {
+	key.Namespaces = namespace
+	return key.String()
+}
+
+// loadSeedResources loads cached resources used to prime a resumed informer.
+// It returns nil when the informer has to start with a full list instead.
+func (im *InformerManager) loadSeedResources(clusterID string, key WatcherKey, namespace, resourceVersion string) []*unstructured.Unstructured {
+	// Selector-scoped watchers do not keep the cache current, their objects may be gone
+	if resourceVersion == "" || im.dbCache == nil || key.MetadataOnly || key.filtered() {
+		return nil
+	}
+
+	gvr := key.GVR
+	resources, _, err := im.dbCache.LoadResources(clusterID, gvr)
+	if err != nil {
+		return nil
+	}
+
+	seed := make([]*unstructured.Unstructured, 0, len(resources))
+	for _, resource := range resources {
+		// Sensitive resources are cached redacted and must not be served from the informer store
+		if im.dbCache.isSensitiveResource(gvr, resource) {
+			return nil
+		}
+		if (namespace == "" || resource.GetNamespace() == namespace) && key.matches(resource) {
+			seed = append(seed, resource)
+		}
+	}
+
+	return seed
+}
+
 // GetClusters returns all cluster connections
 func (im *InformerManager) GetClusters() map[string]*ClusterConnection {
 	im.mu.RLock()
@@ -343,11 +786,19 @@ func (im *InformerManager) GetClusters() map[string]*ClusterConnection {
 	return result
 }
 
-// handleEvent processes informer events and forwards them to the event handler
-func (im *InformerManager) handleEvent(eventType, clusterID string, gvr schema.GroupVersionResource, obj, oldObj any) {
+// handleEvent processes informer events and forwards them to the event handler,
+// it reports whether the event was forwarded
+func (im *InformerManager) handleEvent(eventType, clusterID string, key WatcherKey, obj, oldObj any) bool {
+	gvr := key.GVR
+
+	// Objects deleted while the watch was down arrive as tombstones after a relist
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
 	unstructuredObj, ok := obj.(*unstructured.Unstructured)
 	if !ok {
-		return
+		return false
 	}
 
 	var unstructuredOldObj *unstructured.Unstructured
@@ -357,55 +808,152 @@ func (im *InformerManager) handleEvent(eventType, clusterID string, gvr schema.G
 		}
 	}
 
-	// Update resource version in store
 	resourceVersion := unstructuredObj.GetResourceVersion()
-	if resourceVersion != "" {
-		im.store.setResourceVersion(clusterID, gvr.String(), resourceVersion)
 
+	// Resyncs replay unchanged objects, they are neither cached nor emitted
+	if eventType == "MODIFIED" && unchangedResync(oldObj, obj) {
+		if cluster, err := im.getCluster(clusterID); err == nil {
+			cluster.SuppressedEvents.Add(1)
+		}
+		return false
+	}
+
+	im.updateRootControllers(clusterID, eventType, unstructuredObj, unstructuredOldObj)
+
+	// Events stay in the history after the cluster deleted them
+	if isEventResource(gvr) && eventType != "DELETED" && !key.MetadataOnly {
+		im.recordEventHistory(clusterID, unstructuredObj)
+	}
+
+	// Metadata-only objects would overwrite the full objects in the cache, and objects
+	// leaving the scope of a selector are not deleted from the cluster
+	if resourceVersion != "" && !key.MetadataOnly && !key.filtered() {
 		// Cache the resource in database (non-blocking)
 		if im.dbCache != nil {
 			go func() {
-				if err := im.dbCache.StoreResource(clusterID, gvr, unstructuredObj); err != nil {
+				var err error
+				if eventType == "DELETED" {
+					err = im.dbCache.DeleteResource(clusterID, gvr, unstructuredObj.GetNamespace(), unstructuredObj.GetName())
+				} else {
+					err = im.dbCache.StoreResource(clusterID, gvr, unstructuredObj)
+				}
+				if err != nil {
 					// Log error but don't fail the event processing
-					fmt.Printf("Warning: Failed to cache resource: %v\\n", err)
+					fmt.Printf("Warning: Failed to cache resource: %v\n", err)
 				}
 			}()
 		}
 	}
 
-	// Create event with redacted object for sensitive resources
-	var eventObj *unstructured.Unstructured
-	if im.dbCache != nil && im.dbCache.isSensitiveResource(gvr, unstructuredObj) {
-		eventObj = im.dbCache.redactSensitiveFields(unstructuredObj)
-	} else {
-		eventObj = unstructuredObj
-	}
-
 	event := Event{
 		Type:      eventType,
 		ClusterID: clusterID,
 		GVR:       gvr,
 		Namespace: unstructuredObj.GetNamespace(),
 		Name:      unstructuredObj.GetName(),
-		Object:    eventObj,
-		OldObject: unstructuredOldObj,
+		Object:    im.eventObject(gvr, unstructuredObj),
+		OldObject: im.eventObject(gvr, unstructuredOldObj),
 		Timestamp: time.Now(),
+
+		MetadataOnly: key.MetadataOnly,
+		Watcher:      key,
 	}
 
 	if im.eventHandler != nil {
 		im.eventHandler(event)
 	}
+	return true
+}
+
+// unchangedResync reports whether an update replays an object at the same resource version
+func unchangedResync(oldObj, newObj any) bool {
+	oldObject, oldOK := oldObj.(metav1.Object)
+	newObject, newOK := newObj.(metav1.Object)
+	return oldOK && newOK && newObject.GetResourceVersion() != "" &&
+		oldObject.GetResourceVersion() == newObject.GetResourceVersion()
+}
+
+// SnapshotEvent returns a MODIFIED event carrying the object a watcher of the resource
+// type holds, for consumers that lost track of it. Objects the watchers no longer hold
+// are returned as DELETED events with their namespace and name only.
+func (im *InformerManager) SnapshotEvent(clusterID string, gvr schema.GroupVersionResource, metadataOnly bool, namespace, name string) (*Event, error) {
+	cluster, err := im.getCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	storeKey := name
+	if namespace != "" {
+		storeKey = namespace + "/" + name
+	}
+
+	var obj *unstructured.Unstructured
+	var key WatcherKey
+	watched := false
+	for _, watcher := range cluster.watcherSnapshot() {
+		watcherKey := watcher.currentKey()
+		if watcherKey.GVR != gvr || watcherKey.MetadataOnly != metadataOnly {
+			continue
+		}
+		if !watched || obj == nil {
+			key = watcherKey
+		}
+		watched = true
+		for _, informer := range watcher.Informers() {
+			if item, exists, err := informer.GetStore().GetByKey(storeKey); err == nil && exists && obj == nil {
+				obj, _ = item.(*unstructured.Unstructured)
+			}
+		}
+	}
+	if !watched {
+		return nil, fmt.Errorf("resource %s is not watched in cluster %s", gvr.String(), clusterID)
+	}
+
+	event := &Event{
+		Type:         "MODIFIED",
+		ClusterID:    clusterID,
+		GVR:          gvr,
+		Namespace:    namespace,
+		Name:         name,
+		Object:       im.eventObject(gvr, obj),
+		Timestamp:    time.Now(),
+		MetadataOnly: metadataOnly,
+		Watcher:      key,
+	}
+	if obj == nil {
+		event.Type = "DELETED"
+		event.Object = &unstructured.Unstructured{}
+		event.Object.SetNamespace(namespace)
+		event.Object.SetName(name)
+	}
+	return event, nil
+}
+
+// eventObject returns the object as sent in events, redacted for sensitive resources
+func (im *InformerManager) eventObject(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) *unstructured.Unstructured {
+	if obj != nil && im.dbCache != nil && im.dbCache.isSensitiveResource(gvr, obj) {
+		return im.dbCache.redactSensitiveFields(obj)
+	}
+	return obj
 }
 
 // Shutdown stops all informers and saves state
 func (im *InformerManager) Shutdown() {
+	// Cancelling the manager context stops every watcher
 	im.cancel()
 
 	im.mu.Lock()
 	defer im.mu.Unlock()
 
-	for _, cluster := range im.clusters {
-		cluster.Factory.Shutdown()
+	for id, cluster := range im.clusters {
+		cluster.mu.RLock()
+		// Remember where each watch stopped so the next start can resume from there
+		for key, watcher := range cluster.Informers {
+			for namespace, informer := range watcher.Informers() {
+				im.saveLastSyncResourceVersion(id, key, namespace, informer)
+			}
+		}
+		cluster.mu.RUnlock()
 	}
 
 	im.store.save()
@@ -495,6 +1043,7 @@ func (rvs *ResourceVersionStore) setResourceVersion(clusterID, gvr, version stri
 type DatabaseCacheInterface interface {
 	StoreResource(clusterID string, gvr schema.GroupVersionResource, resource *unstructured.Unstructured) error
 	GetResource(clusterID string, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, bool, error)
+	DeleteResource(clusterID string, gvr schema.GroupVersionResource, namespace, name string) error
 	LoadResources(clusterID string, gvr schema.GroupVersionResource) ([]*unstructured.Unstructured, string, error)
 	GetCacheStats() (map[string]int, error)
 	Close() error
@@ -541,6 +1090,25 @@ func (dc *DatabaseCache) initTables() error {
 		UNIQUE(cluster_id, gvr, namespace, name)
 	);
 	CREATE INDEX IF NOT EXISTS idx_updated_at ON resource_cache(updated_at);
+
+	CREATE TABLE IF NOT EXISTS event_history (
+		uid VARCHAR(36) PRIMARY KEY,
+		cluster_id VARCHAR(255) NOT NULL,
+		regarding_uid VARCHAR(36) NOT NULL,
+		regarding_kind VARCHAR(255) NOT NULL,
+		regarding_namespace VARCHAR(255) NOT NULL,
+		regarding_name VARCHAR(255) NOT NULL,
+		type VARCHAR(32) NOT NULL,
+		reason VARCHAR(255) NOT NULL,
+		count INTEGER NOT NULL,
+		first_seen INTEGER NOT NULL,
+		last_seen INTEGER NOT NULL,
+		data TEXT NOT NULL,
+		stored_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_event_regarding_uid ON event_history(cluster_id, regarding_uid);
+	CREATE INDEX IF NOT EXISTS idx_event_regarding_name ON event_history(cluster_id, regarding_namespace, regarding_name);
+	CREATE INDEX IF NOT EXISTS idx_event_last_seen ON event_history(last_seen);
 	`
 
 	_, err := dc.db.Exec(schema)
@@ -623,6 +1191,20 @@ func (dc *DatabaseCache) GetResource(clusterID string, gvr schema.GroupVersionRe
 	return &resource, isSensitive, nil
 }
 
+// DeleteResource removes a resource from cache
+func (dc *DatabaseCache) DeleteResource(clusterID string, gvr schema.GroupVersionResource, namespace, name string) error {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	query := `
+		DELETE FROM resource_cache
+		WHERE cluster_id = ? AND gvr = ? AND namespace = ? AND name = ?
+	`
+
+	_, err := dc.db.Exec(query, clusterID, gvr.String(), namespace, name)
+	return err
+}
+
 // LoadInitialData loads cached resource data from database for faster startup
 func (im *InformerManager) LoadInitialData(clusterID string, gvr schema.GroupVersionResource) ([]*unstructured.Unstructured, string, error) {
 	if im.dbCache == nil {
@@ -675,11 +1257,13 @@ func (dc *DatabaseCache) LoadResources(clusterID string, gvr schema.GroupVersion
 	return resources, latestResourceVersion, nil
 }
 
-// testAPIAccess tests if we can access the API for a specific GVR
-func (im *InformerManager) testAPIAccess(cluster *ClusterConnection, gvr schema.GroupVersionResource, namespace string) error {
-	// Try to list resources to test permissions
+// testAPIAccess tests if we can access the API for a specific watcher key
+func (im *InformerManager) testAPIAccess(cluster *ClusterConnection, key WatcherKey, namespace string) error {
+	// Try to list resources to test permissions and selectors
 	listOptions := metav1.ListOptions{Limit: 1}
+	key.tweakListOptions(&listOptions)
 
+	gvr := key.GVR
 	if namespace != "" {
 		_, err := cluster.Client.Resource(gvr).Namespace(namespace).List(context.TODO(), listOptions)
 		return err
@@ -878,6 +1462,12 @@ func (im *InformerManager) GetResourceWithSensitivityInfo(clusterID string, gvr
 	}
 
 	// Fallback to API server
+	return im.FetchResource(clusterID, gvr, namespace, name)
+}
+
+// FetchResource gets the current full object from the API server, redacted if it is
+// sensitive. Metadata-only watchers use it to load an object on demand.
+func (im *InformerManager) FetchResource(clusterID string, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, bool, error) {
 	original, err := im.GetOriginalResource(clusterID, gvr, namespace, name)
 	if err != nil {
 		return nil, false, err
@@ -925,6 +1515,14 @@ func (dc *DatabaseCache) GetCacheStats() (map[string]int, error) {
 	}
 	stats["sensitive"] = sensitive
 
+	// Recorded events
+	var events int
+	err = dc.db.QueryRow("SELECT COUNT(*) FROM event_history").Scan(&events)
+	if err != nil {
+		return nil, err
+	}
+	stats["events"] = events
+
 	// Resources by cluster
 	rows, err := dc.db.Query("SELECT cluster_id, COUNT(*) FROM resource_cache GROUP BY cluster_id")
 	if err != nil {
@@ -0,0 +1,181 @@
+package informer
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// PodNetworking are the objects that route traffic to a pod
+type PodNetworking struct {
+	Services       []*unstructured.Unstructured `json:"services"`       // services selecting the pod
+	EndpointSlices []*unstructured.Unstructured `json:"endpointSlices"` // endpoint slices containing the pod
+	Ingresses      []*unstructured.Unstructured `json:"ingresses"`      // ingresses sending to the services
+	HTTPRoutes     []*unstructured.Unstructured `json:"httpRoutes"`     // HTTP routes sending to the services
+	// Unwatched are the resource types no watcher holds full objects of, they are left out
+	Unwatched []string `json:"unwatched,omitempty"` // like "endpointslices.discovery.k8s.io"
+}
+
+// GetNetworkingFor returns the services, endpoint slices, ingresses and HTTP routes
+// of a pod. They are looked up in the informer caches by their indexes, so watchers
+// of these resource types have to be running.
+func (im *InformerManager) GetNetworkingFor(clusterID, namespace, name string) (*PodNetworking, error) {
+	cluster, err := im.getCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+	pod, err := im.getObject(context.TODO(), cluster, podGVR, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	networking := &PodNetworking{
+		Services:       []*unstructured.Unstructured{},
+		EndpointSlices: []*unstructured.Unstructured{},
+		Ingresses:      []*unstructured.Unstructured{},
+		HTTPRoutes:     []*unstructured.Unstructured{},
+	}
+
+	// Services are found by each label of the pod and kept if their whole selector matches
+	podLabels := labels.Set(pod.GetLabels())
+	var selectorPairs []string
+	for key, value := range podLabels {
+		selectorPairs = append(selectorPairs, key+"="+value)
+	}
+	services, watched := cluster.indexedObjects(servicesResource, IndexServiceSelector, selectorPairs)
+	if !watched {
+		networking.Unwatched = append(networking.Unwatched, servicesResource.String())
+	}
+	var serviceKeys []string
+	for _, service := range services {
+		selector, _, _ := unstructured.NestedStringMap(service.Object, "spec", "selector")
+		if service.GetNamespace() != pod.GetNamespace() || !labels.SelectorFromSet(selector).Matches(podLabels) {
+			continue
+		}
+		networking.Services = append(networking.Services, service)
+		serviceKeys = append(serviceKeys, service.GetNamespace()+"/"+service.GetName())
+	}
+
+	// Host network pods share the node IP, so endpoints referencing pods have to reference this one
+	ips := podIPs(pod)
+	endpointSlices, watched := cluster.indexedObjects(endpointSlicesResource, IndexEndpointAddress, ips)
+	if !watched {
+		networking.Unwatched = append(networking.Unwatched, endpointSlicesResource.String())
+	}
+	for _, endpointSlice := range endpointSlices {
+		if endpointSlice.GetNamespace() == pod.GetNamespace() && hasPodEndpoint(endpointSlice, pod, ips) {
+			networking.EndpointSlices = append(networking.EndpointSlices, endpointSlice)
+		}
+	}
+
+	ingresses, watched := cluster.indexedObjects(ingressesResource, IndexBackendService, serviceKeys)
+	if !watched {
+		networking.Unwatched = append(networking.Unwatched, ingressesResource.String())
+	}
+	networking.Ingresses = append(networking.Ingresses, ingresses...)
+
+	// Routes of other namespaces may send to the services too
+	httpRoutes, watched := cluster.indexedObjects(httpRoutesResource, IndexBackendService, serviceKeys)
+	if !watched {
+		networking.Unwatched = append(networking.Unwatched, httpRoutesResource.String())
+	}
+	networking.HTTPRoutes = append(networking.HTTPRoutes, httpRoutes...)
+
+	return networking, nil
+}
+
+// indexedObjects returns the objects of a resource type whose index contains any of
+// the values, sorted by namespace and name. It reports whether a watcher holding
+// full objects of the resource type is running.
+func (c *ClusterConnection) indexedObjects(resource schema.GroupResource, indexName string, values []string) ([]*unstructured.Unstructured, bool) {
+	c.mu.RLock()
+	var watchers []*ResourceWatcher
+	for key, watcher := range c.Informers {
+		// Metadata-only objects lack the indexed fields
+		if key.GVR.GroupResource() == resource && !key.MetadataOnly {
+			watchers = append(watchers, watcher)
+		}
+	}
+	c.mu.RUnlock()
+
+	seen := make(map[types.UID]struct{})
+	var objects []*unstructured.Unstructured
+	for _, watcher := range watchers {
+		for _, value := range values {
+			items, err := watcher.byIndex(indexName, value)
+			if err != nil {
+				continue
+			}
+			for _, item := range items {
+				obj, ok := item.(*unstructured.Unstructured)
+				if !ok {
+					continue
+				}
+				if _, exists := seen[obj.GetUID()]; !exists {
+					seen[obj.GetUID()] = struct{}{}
+					objects = append(objects, obj)
+				}
+			}
+		}
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].GetNamespace() != objects[j].GetNamespace() {
+			return objects[i].GetNamespace() < objects[j].GetNamespace()
+		}
+		return objects[i].GetName() < objects[j].GetName()
+	})
+	return objects, len(watchers) > 0
+}
+
+// hasPodEndpoint reports whether an endpoint slice has an endpoint for a pod: one
+// with an address of the pod that references the pod, or references no object
+func hasPodEndpoint(endpointSlice, pod *unstructured.Unstructured, ips []string) bool {
+	endpoints, _, _ := unstructured.NestedSlice(endpointSlice.Object, "endpoints")
+	for _, item := range endpoints {
+		endpoint, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		if targetUID, found, _ := unstructured.NestedString(endpoint, "targetRef", "uid"); found && targetUID != string(pod.GetUID()) {
+			continue
+		}
+		addresses, _, _ := unstructured.NestedStringSlice(endpoint, "addresses")
+		for _, address := range addresses {
+			if slices.Contains(ips, address) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// podIPs returns the IP addresses of a pod
+func podIPs(pod *unstructured.Unstructured) []string {
+	var ips []string
+	seen := make(map[string]struct{})
+	addIP := func(ip string) {
+		if _, exists := seen[ip]; ip != "" && !exists {
+			seen[ip] = struct{}{}
+			ips = append(ips, ip)
+		}
+	}
+
+	podIP, _, _ := unstructured.NestedString(pod.Object, "status", "podIP")
+	addIP(podIP)
+	podIPList, _, _ := unstructured.NestedSlice(pod.Object, "status", "podIPs")
+	for _, item := range podIPList {
+		if itemMap, ok := item.(map[string]any); ok {
+			ip, _ := itemMap["ip"].(string)
+			addIP(ip)
+		}
+	}
+	return ips
+}
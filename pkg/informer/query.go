@@ -0,0 +1,302 @@
+package informer
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ResourceQuery selects, sorts and pages the objects held by the watchers of a cluster
+type ResourceQuery struct {
+	GVR           schema.GroupVersionResource `json:"gvr"`
+	Namespace     string                      `json:"namespace,omitempty"` // empty for all watched namespaces
+	LabelSelector string                      `json:"labelSelector,omitempty"`
+	FieldSelector string                      `json:"fieldSelector,omitempty"`
+	NameContains  string                      `json:"nameContains,omitempty"` // case insensitive
+	// IndexName and IndexValue select the objects of an index, like the pods of a node
+	// with IndexNodeName or the children of an owner with IndexOwnerUID
+	IndexName  string `json:"indexName,omitempty"`
+	IndexValue string `json:"indexValue,omitempty"`
+	// SortBy are field paths like metadata.creationTimestamp, prefixed with - to sort
+	// descending. Objects are ordered by namespace and name after the sort keys.
+	SortBy   []string `json:"sortBy,omitempty"`
+	Limit    int      `json:"limit,omitempty"`    // objects per page, 0 for all
+	Continue string   `json:"continue,omitempty"` // cursor returned with the previous page
+}
+
+// ResourcePage is a page of a resource query
+type ResourcePage struct {
+	Items    []*unstructured.Unstructured `json:"items"`
+	Total    int                          `json:"total"`              // matching objects on all pages
+	Continue string                       `json:"continue,omitempty"` // empty on the last page
+}
+
+// queryCursor is the position after the last object of a page. Positions are
+// sort values, so objects added or removed meanwhile do not shift the pages.
+type queryCursor struct {
+	Values    []any  `json:"v"`
+	Namespace string `json:"ns"`
+	Name      string `json:"n"`
+}
+
+// sortKey is a parsed sort key of a query
+type sortKey struct {
+	path       []string
+	descending bool
+}
+
+// queryEntry is a matching object with its sort values
+type queryEntry struct {
+	obj    *unstructured.Unstructured
+	cursor queryCursor
+}
+
+// ListResources queries the objects of a resource type held by the informers of a
+// running watcher, without contacting the apiserver. A watcher for the resource
+// type has to cover the namespace and the selectors of the query.
+func (im *InformerManager) ListResources(clusterID string, query ResourceQuery) (*ResourcePage, error) {
+	cluster, err := im.getCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	labelSelector, err := labels.Parse(query.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector %q: %w", query.LabelSelector, err)
+	}
+	fieldSelector, err := fields.ParseSelector(query.FieldSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid field selector %q: %w", query.FieldSelector, err)
+	}
+
+	var after *queryCursor
+	if query.Continue != "" {
+		if after, err = decodeQueryCursor(query.Continue); err != nil {
+			return nil, err
+		}
+	}
+
+	sortKeys := make([]sortKey, 0, len(query.SortBy))
+	for _, field := range query.SortBy {
+		path, descending := strings.CutPrefix(field, "-")
+		if path == "" {
+			return nil, fmt.Errorf("invalid sort key %q", field)
+		}
+		sortKeys = append(sortKeys, sortKey{path: strings.Split(path, "."), descending: descending})
+	}
+
+	watcher := cluster.queryWatcher(query.GVR, query.Namespace, labelSelector.String(), fieldSelector.String())
+	if watcher == nil {
+		return nil, fmt.Errorf("no watcher for %s covers the query, add a resource watcher first", query.GVR.String())
+	}
+
+	var items []any
+	if query.IndexName != "" {
+		if items, err = watcher.byIndex(query.IndexName, query.IndexValue); err != nil {
+			return nil, err
+		}
+	} else {
+		items = watcher.objectsIn(query.Namespace)
+	}
+
+	nameContains := strings.ToLower(query.NameContains)
+	var entries []queryEntry
+	for _, item := range items {
+		obj, ok := item.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		if query.IndexName != "" && query.Namespace != "" && obj.GetNamespace() != query.Namespace {
+			continue
+		}
+		if nameContains != "" && !strings.Contains(strings.ToLower(obj.GetName()), nameContains) {
+			continue
+		}
+		if !selectorsMatch(obj, labelSelector, fieldSelector) {
+			continue
+		}
+
+		entry := queryEntry{obj: obj, cursor: queryCursor{Namespace: obj.GetNamespace(), Name: obj.GetName()}}
+		for _, key := range sortKeys {
+			value, _, _ := unstructured.NestedFieldNoCopy(obj.Object, key.path...)
+			entry.cursor.Values = append(entry.cursor.Values, sortValue(value))
+		}
+		entries = append(entries, entry)
+	}
+
+	slices.SortFunc(entries, func(a, b queryEntry) int {
+		return compareCursors(sortKeys, a.cursor, b.cursor)
+	})
+
+	start := 0
+	if after != nil {
+		if len(after.Values) != len(sortKeys) {
+			return nil, fmt.Errorf("continue token does not match the sort keys of the query")
+		}
+		start = sort.Search(len(entries), func(i int) bool {
+			return compareCursors(sortKeys, entries[i].cursor, *after) > 0
+		})
+	}
+
+	end := len(entries)
+	if query.Limit > 0 && start+query.Limit < end {
+		end = start + query.Limit
+	}
+
+	page := &ResourcePage{
+		Items: make([]*unstructured.Unstructured, 0, end-start),
+		Total: len(entries),
+	}
+	for _, entry := range entries[start:end] {
+		if im.dbCache != nil && im.dbCache.isSensitiveResource(query.GVR, entry.obj) {
+			page.Items = append(page.Items, im.dbCache.redactSensitiveFields(entry.obj))
+		} else {
+			page.Items = append(page.Items, entry.obj)
+		}
+	}
+	if end < len(entries) {
+		page.Continue = encodeQueryCursor(entries[end-1].cursor)
+	}
+
+	return page, nil
+}
+
+// queryWatcher returns the watcher best suited to answer a query: one without
+// selectors or with the selectors of the query, covering the namespace, and
+// preferably holding full objects of all namespaces
+func (c *ClusterConnection) queryWatcher(gvr schema.GroupVersionResource, namespace, labelSelector, fieldSelector string) *ResourceWatcher {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var best *ResourceWatcher
+	bestScore := -1
+	for key, watcher := range c.Informers {
+		if key.GVR != gvr {
+			continue
+		}
+		if namespace != "" && key.Namespaces != "" && !slices.Contains(key.NamespaceList(), namespace) {
+			continue
+		}
+
+		unfiltered := !key.filtered()
+		if !unfiltered && (key.LabelSelector != labelSelector || key.FieldSelector != fieldSelector) {
+			continue
+		}
+
+		score := 0
+		if !key.MetadataOnly {
+			score += 4
+		}
+		if key.Namespaces == "" {
+			score += 2
+		}
+		if unfiltered {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = watcher, score
+		}
+	}
+	return best
+}
+
+// objectsIn returns the objects of a namespace held by the watcher, or all of them for an empty namespace
+func (w *ResourceWatcher) objectsIn(namespace string) []any {
+	if namespace == "" {
+		return w.List()
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if scoped, exists := w.informers[namespace]; exists {
+		return scoped.informer.GetStore().List()
+	}
+	if scoped, exists := w.informers[""]; exists {
+		objects, _ := scoped.informer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+		return objects
+	}
+	return nil
+}
+
+// sortValue normalizes a field value for sorting. Values keep their order after
+// a JSON round trip through a continue token.
+func sortValue(value any) any {
+	switch v := value.(type) {
+	case nil, string, bool, float64:
+		return v
+	case int64:
+		return float64(v)
+	case int:
+		return float64(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// compareValues orders missing values first, numbers numerically and everything else as text
+func compareValues(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	aNumber, aIsNumber := a.(float64)
+	bNumber, bIsNumber := b.(float64)
+	if aIsNumber && bIsNumber {
+		return cmp.Compare(aNumber, bNumber)
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// compareCursors orders positions by the sort keys, then by namespace and name
+func compareCursors(sortKeys []sortKey, a, b queryCursor) int {
+	for i, key := range sortKeys {
+		result := compareValues(a.Values[i], b.Values[i])
+		if key.descending {
+			result = -result
+		}
+		if result != 0 {
+			return result
+		}
+	}
+
+	if result := strings.Compare(a.Namespace, b.Namespace); result != 0 {
+		return result
+	}
+	return strings.Compare(a.Name, b.Name)
+}
+
+// encodeQueryCursor encodes a position as an opaque continue token
+func encodeQueryCursor(cursor queryCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeQueryCursor decodes a continue token
+func decodeQueryCursor(token string) (*queryCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid continue token: %w", err)
+	}
+
+	var cursor queryCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("invalid continue token: %w", err)
+	}
+	return &cursor, nil
+}
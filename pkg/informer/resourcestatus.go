@@ -0,0 +1,141 @@
+package informer
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Readiness states of a resource
+const (
+	StatusReady       = "ready"
+	StatusProgressing = "progressing"
+	StatusFailed      = "failed"
+	StatusUnknown     = "unknown" // the resource reports no readiness
+)
+
+// waitingFailureReasons are container waiting reasons that will not resolve on their own
+var waitingFailureReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+}
+
+// ResourceStatus returns the readiness of a resource and a short explanation.
+// Pods, workloads and jobs are judged by their status fields, other resources
+// by their Ready or Available condition.
+func ResourceStatus(obj *unstructured.Unstructured) (string, string) {
+	switch obj.GetKind() {
+	case "Pod":
+		return podStatus(obj)
+	case "Deployment", "ReplicaSet", "StatefulSet", "ReplicationController":
+		return replicasStatus(obj)
+	case "DaemonSet":
+		return daemonSetStatus(obj)
+	case "Job":
+		return jobStatus(obj)
+	}
+
+	for _, conditionType := range []string{"Ready", "Available"} {
+		if status, reason, found := condition(obj, conditionType); found {
+			if status == "True" {
+				return StatusReady, ""
+			}
+			return StatusProgressing, reason
+		}
+	}
+	return StatusUnknown, ""
+}
+
+// podStatus judges a pod by its phase, readiness and container states
+func podStatus(obj *unstructured.Unstructured) (string, string) {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	switch phase {
+	case "Succeeded":
+		return StatusReady, "Completed"
+	case "Failed":
+		reason, _, _ := unstructured.NestedString(obj.Object, "status", "reason")
+		return StatusFailed, reason
+	}
+
+	for _, field := range []string{"initContainerStatuses", "containerStatuses"} {
+		statuses, _, _ := unstructured.NestedSlice(obj.Object, "status", field)
+		for _, status := range statuses {
+			statusMap, ok := status.(map[string]any)
+			if !ok {
+				continue
+			}
+			reason, _, _ := unstructured.NestedString(statusMap, "state", "waiting", "reason")
+			if waitingFailureReasons[reason] {
+				return StatusFailed, reason
+			}
+		}
+	}
+
+	if status, _, _ := condition(obj, "Ready"); status == "True" {
+		return StatusReady, ""
+	}
+	if phase == "" {
+		phase = "Pending"
+	}
+	return StatusProgressing, phase
+}
+
+// replicasStatus judges a workload by its ready replicas
+func replicasStatus(obj *unstructured.Unstructured) (string, string) {
+	desired, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if !found {
+		desired = 1
+	}
+	ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+
+	if obj.GetKind() == "Deployment" {
+		if status, reason, found := condition(obj, "Progressing"); found && status == "False" {
+			return StatusFailed, reason
+		}
+	}
+	if ready >= desired {
+		return StatusReady, fmt.Sprintf("%d/%d ready", ready, desired)
+	}
+	return StatusProgressing, fmt.Sprintf("%d/%d ready", ready, desired)
+}
+
+// daemonSetStatus judges a daemon set by its ready pods
+func daemonSetStatus(obj *unstructured.Unstructured) (string, string) {
+	desired, _, _ := unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled")
+	ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "numberReady")
+
+	if ready >= desired {
+		return StatusReady, fmt.Sprintf("%d/%d ready", ready, desired)
+	}
+	return StatusProgressing, fmt.Sprintf("%d/%d ready", ready, desired)
+}
+
+// jobStatus judges a job by its completion conditions
+func jobStatus(obj *unstructured.Unstructured) (string, string) {
+	if status, _, _ := condition(obj, "Complete"); status == "True" {
+		return StatusReady, "Completed"
+	}
+	if status, reason, _ := condition(obj, "Failed"); status == "True" {
+		return StatusFailed, reason
+	}
+	return StatusProgressing, "Running"
+}
+
+// condition returns the status and reason of a status condition
+func condition(obj *unstructured.Unstructured, conditionType string) (string, string, bool) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, item := range conditions {
+		conditionMap, ok := item.(map[string]any)
+		if !ok || conditionMap["type"] != conditionType {
+			continue
+		}
+		status, _ := conditionMap["status"].(string)
+		reason, _ := conditionMap["reason"].(string)
+		return status, reason, true
+	}
+	return "", "", false
+}
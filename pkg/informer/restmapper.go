@@ -0,0 +1,62 @@
+package informer
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ResolvedResource is a resource type resolved from a resource name, short name or kind
+type ResolvedResource struct {
+	GVR        schema.GroupVersionResource `json:"gvr"`
+	GVK        schema.GroupVersionKind     `json:"gvk"`
+	Namespaced bool                        `json:"namespaced"`
+}
+
+// ResolveResource resolves a resource type the way kubectl does. The name may be a
+// resource ("pods", "deployments.apps", "deployments.v1.apps"), a short name
+// ("po", "deploy") or a kind ("Pod", "Deployment.apps", "Deployment.v1.apps").
+// Names served by several groups or versions resolve to the preferred one.
+func (im *InformerManager) ResolveResource(clusterID, name string) (*ResolvedResource, error) {
+	cluster, err := im.getCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, err := resolveMapping(cluster.Mapper, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve resource type %q: %w", name, err)
+	}
+
+	return &ResolvedResource{
+		GVR:        mapping.Resource,
+		GVK:        mapping.GroupVersionKind,
+		Namespaced: mapping.Scope.Name() == meta.RESTScopeNameNamespace,
+	}, nil
+}
+
+// resolveMapping tries the name as a resource first and as a kind otherwise
+func resolveMapping(mapper meta.RESTMapper, name string) (*meta.RESTMapping, error) {
+	fullySpecifiedGVR, groupResource := schema.ParseResourceArg(name)
+
+	var gvk schema.GroupVersionKind
+	if fullySpecifiedGVR != nil {
+		gvk, _ = mapper.KindFor(*fullySpecifiedGVR)
+	}
+	if gvk.Empty() {
+		gvk, _ = mapper.KindFor(groupResource.WithVersion(""))
+	}
+	if !gvk.Empty() {
+		return mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+
+	fullySpecifiedGVK, groupKind := schema.ParseKindArg(name)
+	if fullySpecifiedGVK != nil {
+		if mapping, err := mapper.RESTMapping(fullySpecifiedGVK.GroupKind(), fullySpecifiedGVK.Version); err == nil {
+			return mapping, nil
+		}
+	}
+
+	return mapper.RESTMapping(groupKind)
+}
@@ -0,0 +1,358 @@
+package informer
+
+import (
+	"context"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ObjectReference identifies an object of a cluster
+type ObjectReference struct {
+	GVR       schema.GroupVersionResource `json:"gvr"`
+	Namespace string                      `json:"namespace,omitempty"`
+	Name      string                      `json:"name"`
+}
+
+// RootController is the topmost owner of an object, like the Deployment of a pod
+type RootController struct {
+	GVR       schema.GroupVersionResource `json:"gvr"`
+	Kind      string                      `json:"kind"`
+	Namespace string                      `json:"namespace,omitempty"`
+	Name      string                      `json:"name"`
+	UID       string                      `json:"uid"`
+}
+
+// RootControllerResult is the root controller of an object. Root is nil for objects
+// without an owner.
+type RootControllerResult struct {
+	Object ObjectReference `json:"object"`
+	Root   *RootController `json:"root,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// rootResolution is the root controller of an object and the owners passed on the way
+type rootResolution struct {
+	root  *RootController
+	chain []types.UID
+}
+
+// rootControllerEntry is an object whose root controller was resolved
+type rootControllerEntry struct {
+	object     ObjectReference
+	resolution *rootResolution
+}
+
+// rootControllerCache keeps the resolved root controllers of a cluster, so that
+// changes of the owners on the way can update them
+type rootControllerCache struct {
+	entries    map[types.UID]*rootControllerEntry
+	dependents map[types.UID]map[types.UID]struct{} // owner UID -> objects resolved through it
+	mu         sync.Mutex
+}
+
+func newRootControllerCache() *rootControllerCache {
+	return &rootControllerCache{
+		entries:    make(map[types.UID]*rootControllerEntry),
+		dependents: make(map[types.UID]map[types.UID]struct{}),
+	}
+}
+
+// get returns the cached resolution of an object
+func (c *rootControllerCache) get(uid types.UID) *rootResolution {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, exists := c.entries[uid]; exists {
+		return entry.resolution
+	}
+	return nil
+}
+
+// track caches the resolution of an object and reports whether its root changed
+func (c *rootControllerCache) track(uid types.UID, object ObjectReference, resolution *rootResolution) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	previous := c.untrack(uid)
+	c.entries[uid] = &rootControllerEntry{object: object, resolution: resolution}
+	for _, ownerUID := range resolution.chain {
+		if c.dependents[ownerUID] == nil {
+			c.dependents[ownerUID] = make(map[types.UID]struct{})
+		}
+		c.dependents[ownerUID][uid] = struct{}{}
+	}
+
+	return previous == nil || !sameRoot(previous.resolution.root, resolution.root)
+}
+
+// untrack drops an object from the cache, the caller holds the lock
+func (c *rootControllerCache) untrack(uid types.UID) *rootControllerEntry {
+	entry, exists := c.entries[uid]
+	if !exists {
+		return nil
+	}
+
+	delete(c.entries, uid)
+	for _, ownerUID := range entry.resolution.chain {
+		delete(c.dependents[ownerUID], uid)
+		if len(c.dependents[ownerUID]) == 0 {
+			delete(c.dependents, ownerUID)
+		}
+	}
+	return entry
+}
+
+// affectedBy returns the cached objects whose root controller may change with an
+// event. Objects resolve again when they or an owner on the way change owners, when
+// an owner is deleted, or when a missing owner appears.
+func (c *rootControllerCache) affectedBy(eventType string, obj, oldObj *unstructured.Unstructured) map[types.UID]ObjectReference {
+	uid := obj.GetUID()
+	ownersChanged := eventType == "MODIFIED" && oldObj != nil && controllerUID(oldObj) != controllerUID(obj)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	affected := make(map[types.UID]ObjectReference)
+	if eventType == "DELETED" || eventType == "ADDED" || ownersChanged {
+		for dependentUID := range c.dependents[uid] {
+			affected[dependentUID] = c.entries[dependentUID].object
+		}
+	}
+
+	if entry, exists := c.entries[uid]; exists {
+		switch {
+		case eventType == "DELETED":
+			c.untrack(uid)
+		case ownersChanged:
+			affected[uid] = entry.object
+		}
+	}
+	return affected
+}
+
+// prune drops the cached objects keep rejects
+func (c *rootControllerCache) prune(keep func(ObjectReference) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for uid, entry := range c.entries {
+		if !keep(entry.object) {
+			c.untrack(uid)
+		}
+	}
+}
+
+// reset drops every cached object and returns them
+func (c *rootControllerCache) reset() map[types.UID]ObjectReference {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	objects := make(map[types.UID]ObjectReference, len(c.entries))
+	for uid, entry := range c.entries {
+		objects[uid] = entry.object
+	}
+	c.entries = make(map[types.UID]*rootControllerEntry)
+	c.dependents = make(map[types.UID]map[types.UID]struct{})
+	return objects
+}
+
+// SetRootControllerHandler sets the handler notified when the root controllers of
+// objects resolved before change
+func (im *InformerManager) SetRootControllerHandler(handler func(clusterID string, results []RootControllerResult)) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.rootControllerHandler = handler
+}
+
+// SetExcludedOwnerKinds sets the owner kinds root controller resolution stops at,
+// like "ReplicaSet" or "Rollout.argoproj.io". Kinds without a group match any group.
+// Objects resolved before are resolved again and changes reported to the handler.
+func (im *InformerManager) SetExcludedOwnerKinds(kinds []string) {
+	excluded := make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		excluded[kind] = true
+	}
+
+	im.mu.Lock()
+	im.excludedOwnerKinds = excluded
+	clusters := make([]*ClusterConnection, 0, len(im.clusters))
+	for _, cluster := range im.clusters {
+		clusters = append(clusters, cluster)
+	}
+	im.mu.Unlock()
+
+	for _, cluster := range clusters {
+		if objects := cluster.rootControllers.reset(); len(objects) > 0 {
+			go im.refreshRootControllers(cluster, objects)
+		}
+	}
+}
+
+// ResolveRootControllers returns the root controllers of objects in their order,
+// following controller references from the informer caches and fetching owners no
+// watcher holds. Changes of watched owners are reported to the root controller
+// handler for as long as a watcher holds the objects.
+func (im *InformerManager) ResolveRootControllers(clusterID string, objects []ObjectReference) ([]RootControllerResult, error) {
+	cluster, err := im.getCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Objects of the same workload share their owners, each is looked up once
+	resolutions := make(map[types.UID]*rootResolution)
+	results := make([]RootControllerResult, len(objects))
+	for i, object := range objects {
+		results[i], _ = im.resolveRootController(context.TODO(), cluster, object, resolutions, true)
+	}
+	return results, nil
+}
+
+// resolveRootController resolves the root controller of an object and caches it,
+// it reports whether the root differs from the cached one
+func (im *InformerManager) resolveRootController(ctx context.Context, cluster *ClusterConnection, object ObjectReference, resolutions map[types.UID]*rootResolution, useCache bool) (RootControllerResult, bool) {
+	result := RootControllerResult{Object: object}
+
+	obj, err := im.getObject(ctx, cluster, object.GVR, object.Namespace, object.Name)
+	if err != nil {
+		result.Error = err.Error()
+		return result, false
+	}
+
+	if useCache {
+		if resolution := cluster.rootControllers.get(obj.GetUID()); resolution != nil {
+			result.Root = resolution.root
+			return result, false
+		}
+	}
+
+	resolution, err := im.resolveRoot(ctx, cluster, obj, im.excludedKinds(), resolutions, 0)
+	if err != nil {
+		result.Error = err.Error()
+		return result, false
+	}
+
+	result.Root = resolution.root
+	if !cluster.holdsObject(object.GVR, object.Namespace, object.Name) {
+		return result, false // Nothing would report the deletion of the object
+	}
+	return result, cluster.rootControllers.track(obj.GetUID(), object, resolution)
+}
+
+// resolveRoot follows the controller references of an object up to its root.
+// Owners that are gone or excluded end the walk.
+func (im *InformerManager) resolveRoot(ctx context.Context, cluster *ClusterConnection, obj *unstructured.Unstructured, excluded map[string]bool, resolutions map[types.UID]*rootResolution, depth int) (*rootResolution, error) {
+	ownerRef := controllerOf(obj)
+	if ownerRef == nil || depth >= maxOwnerDepth {
+		return &rootResolution{}, nil
+	}
+
+	gvk := schema.FromAPIVersionAndKind(ownerRef.APIVersion, ownerRef.Kind)
+	if excluded[gvk.Kind] || excluded[gvk.GroupKind().String()] {
+		return &rootResolution{}, nil
+	}
+
+	ownerResolution, exists := resolutions[ownerRef.UID]
+	if !exists {
+		gvr, owner, err := im.getOwner(ctx, cluster, obj)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		if owner == nil {
+			// The owner is watched for in case it appears later
+			return &rootResolution{chain: []types.UID{ownerRef.UID}}, nil
+		}
+
+		ownerResolution, err = im.resolveRoot(ctx, cluster, owner, excluded, resolutions, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if ownerResolution.root == nil {
+			ownerResolution = &rootResolution{
+				root: &RootController{
+					GVR:       gvr,
+					Kind:      ownerRef.Kind,
+					Namespace: owner.GetNamespace(),
+					Name:      owner.GetName(),
+					UID:       string(owner.GetUID()),
+				},
+				chain: ownerResolution.chain,
+			}
+		}
+		resolutions[ownerRef.UID] = ownerResolution
+	}
+
+	return &rootResolution{
+		root:  ownerResolution.root,
+		chain: append([]types.UID{ownerRef.UID}, ownerResolution.chain...),
+	}, nil
+}
+
+// updateRootControllers resolves the cached objects an event may affect again
+func (im *InformerManager) updateRootControllers(clusterID, eventType string, obj, oldObj *unstructured.Unstructured) {
+	cluster, err := im.getCluster(clusterID)
+	if err != nil {
+		return
+	}
+
+	if affected := cluster.rootControllers.affectedBy(eventType, obj, oldObj); len(affected) > 0 {
+		go im.refreshRootControllers(cluster, affected)
+	}
+}
+
+// pruneRootControllers drops the cached objects no watcher holds anymore, it is
+// called when watchers stop
+func (c *ClusterConnection) pruneRootControllers() {
+	c.rootControllers.prune(func(object ObjectReference) bool {
+		return c.holdsObject(object.GVR, object.Namespace, object.Name)
+	})
+}
+
+// refreshRootControllers resolves objects again and reports those whose root changed
+func (im *InformerManager) refreshRootControllers(cluster *ClusterConnection, objects map[types.UID]ObjectReference) {
+	resolutions := make(map[types.UID]*rootResolution)
+
+	var changed []RootControllerResult
+	for _, object := range objects {
+		result, rootChanged := im.resolveRootController(im.ctx, cluster, object, resolutions, false)
+		if rootChanged {
+			changed = append(changed, result)
+		}
+	}
+
+	im.mu.RLock()
+	handler := im.rootControllerHandler
+	im.mu.RUnlock()
+
+	if handler != nil && len(changed) > 0 {
+		handler(cluster.ID, changed)
+	}
+}
+
+// excludedKinds returns the owner kinds root controller resolution stops at
+func (im *InformerManager) excludedKinds() map[string]bool {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	return im.excludedOwnerKinds
+}
+
+// controllerUID returns the UID of the owner an object is resolved through
+func controllerUID(obj *unstructured.Unstructured) types.UID {
+	if ownerRef := controllerOf(obj); ownerRef != nil {
+		return ownerRef.UID
+	}
+	return ""
+}
+
+// sameRoot reports whether two resolutions have the same root controller
+func sameRoot(a, b *RootController) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.UID == b.UID
+}
@@ -0,0 +1,128 @@
+package informer
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/cache"
+)
+
+// eventRateWindow is the number of seconds the event rate of a watcher is averaged over
+const eventRateWindow = 10
+
+// WatcherStatus describes the state of a running watcher
+type WatcherStatus struct {
+	Key                 WatcherKey  `json:"key"`
+	HasSynced           bool        `json:"hasSynced"`
+	ObjectCount         int         `json:"objectCount"`
+	LastEventTime       time.Time   `json:"lastEventTime"` // zero until the first event
+	LastResourceVersion string      `json:"lastResourceVersion"`
+	EventsPerSecond     float64     `json:"eventsPerSecond"`
+	LastError           *WatchError `json:"lastError,omitempty"`
+}
+
+// watcherStats tracks the events delivered by a watcher
+type watcherStats struct {
+	lastEventTime       time.Time
+	lastResourceVersion string
+	buckets             [eventRateWindow]uint64 // events per second
+	bucketSeconds       [eventRateWindow]int64  // unix second of each bucket
+	mu                  sync.Mutex
+}
+
+// record counts a delivered event
+func (s *watcherStats) record(resourceVersion string) {
+	now := time.Now()
+	second := now.Unix()
+	bucket := second % eventRateWindow
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastEventTime = now
+	if resourceVersion != "" {
+		s.lastResourceVersion = resourceVersion
+	}
+	if s.bucketSeconds[bucket] != second {
+		s.bucketSeconds[bucket] = second
+		s.buckets[bucket] = 0
+	}
+	s.buckets[bucket]++
+}
+
+// rate returns the average events per second over the rate window
+func (s *watcherStats) rate() float64 {
+	oldest := time.Now().Unix() - eventRateWindow
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events uint64
+	for i, second := range s.bucketSeconds {
+		if second > oldest {
+			events += s.buckets[i]
+		}
+	}
+	return float64(events) / eventRateWindow
+}
+
+// recordEvent counts an event delivered by one of the watcher's informers
+func (w *ResourceWatcher) recordEvent(obj any) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	var resourceVersion string
+	if object, err := meta.Accessor(obj); err == nil {
+		resourceVersion = object.GetResourceVersion()
+	}
+	w.stats.record(resourceVersion)
+}
+
+// Status returns the current state of the watcher
+func (w *ResourceWatcher) Status() WatcherStatus {
+	status := WatcherStatus{
+		HasSynced:       w.HasSynced(),
+		EventsPerSecond: w.stats.rate(),
+	}
+
+	w.mu.RLock()
+	status.Key = w.Key
+	for _, scoped := range w.informers {
+		status.ObjectCount += len(scoped.informer.GetIndexer().ListKeys())
+		if scoped.lastError != nil && (status.LastError == nil || scoped.lastError.Timestamp.After(status.LastError.Timestamp)) {
+			lastError := *scoped.lastError
+			status.LastError = &lastError
+		}
+	}
+	w.mu.RUnlock()
+
+	w.stats.mu.Lock()
+	status.LastEventTime = w.stats.lastEventTime
+	status.LastResourceVersion = w.stats.lastResourceVersion
+	w.stats.mu.Unlock()
+
+	return status
+}
+
+// GetWatcherStatus returns the state of all watchers of a cluster
+func (im *InformerManager) GetWatcherStatus(clusterID string) ([]WatcherStatus, error) {
+	cluster, err := im.getCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster.mu.RLock()
+	result := make([]WatcherStatus, 0, len(cluster.Informers))
+	for _, watcher := range cluster.Informers {
+		result = append(result, watcher.Status())
+	}
+	cluster.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key.String() < result[j].Key.String()
+	})
+	return result, nil
+}
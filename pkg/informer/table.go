@@ -0,0 +1,237 @@
+package informer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+)
+
+// tableAcceptHeader asks the apiserver to render lists and watch events as tables,
+// falling back to plain JSON for servers that cannot
+const tableAcceptHeader = "application/json;as=Table;v=v1;g=meta.k8s.io,application/json"
+
+// ResourceTable is a server-side rendered table of resources, with the column
+// definitions of kubectl and CRD additionalPrinterColumns
+type ResourceTable struct {
+	Columns         []metav1.TableColumnDefinition `json:"columns"`
+	Rows            []TableRow                     `json:"rows"`
+	ResourceVersion string                         `json:"resourceVersion"`
+}
+
+// TableRow is a table row with the identity of the object it renders
+type TableRow struct {
+	Namespace       string `json:"namespace,omitempty"`
+	Name            string `json:"name"`
+	UID             string `json:"uid"`
+	ResourceVersion string `json:"resourceVersion"`
+	Cells           []any  `json:"cells"`
+}
+
+// TableEvent is a change of a watched table. RESET replaces the whole table,
+// which happens when the watch had to be restarted with a new list.
+type TableEvent struct {
+	Type  string         `json:"type"` // ADDED, MODIFIED, DELETED, RESET
+	Rows  []TableRow     `json:"rows,omitempty"`
+	Table *ResourceTable `json:"table,omitempty"`
+}
+
+// TableWatch keeps a rendered table up to date until it is stopped
+type TableWatch struct {
+	cancel   context.CancelFunc
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// Stop stops the watch and waits for it to exit
+func (w *TableWatch) Stop() {
+	w.stopOnce.Do(w.cancel)
+	<-w.done
+}
+
+// ListResourceTable lists resources rendered as a table by the apiserver
+func (im *InformerManager) ListResourceTable(clusterID string, gvr schema.GroupVersionResource, namespace, labelSelector string) (*ResourceTable, error) {
+	cluster, err := im.getCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return listTable(context.TODO(), cluster.REST, gvr, namespace, labelSelector)
+}
+
+// WatchResourceTable lists resources rendered as a table and watches for row changes
+// from the resource version of the list. Changes are passed to the handler until
+// the returned watch is stopped.
+func (im *InformerManager) WatchResourceTable(clusterID string, gvr schema.GroupVersionResource, namespace, labelSelector string, handler func(TableEvent)) (*ResourceTable, *TableWatch, error) {
+	cluster, err := im.getCluster(clusterID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	table, err := listTable(context.TODO(), cluster.REST, gvr, namespace, labelSelector)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithCancel(im.ctx)
+	tableWatch := &TableWatch{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(tableWatch.done)
+
+		resourceVersion := table.ResourceVersion
+		for ctx.Err() == nil {
+			err := streamTable(ctx, cluster.REST, gvr, namespace, labelSelector, resourceVersion, func(eventType string, rows []TableRow, rowsVersion string) {
+				resourceVersion = rowsVersion
+				handler(TableEvent{Type: eventType, Rows: rows})
+			})
+			if ctx.Err() != nil {
+				return
+			}
+
+			// The watch ends on server timeouts too, only a stale version needs a relist
+			if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+				relisted, err := listTable(ctx, cluster.REST, gvr, namespace, labelSelector)
+				if err == nil {
+					resourceVersion = relisted.ResourceVersion
+					handler(TableEvent{Type: "RESET", Table: relisted})
+					continue
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}()
+
+	return table, tableWatch, nil
+}
+
+// tableRequest builds a table request for a resource
+func tableRequest(client rest.Interface, gvr schema.GroupVersionResource, namespace, labelSelector string) *rest.Request {
+	segments := []string{"/apis", gvr.Group, gvr.Version}
+	if gvr.Group == "" {
+		segments = []string{"/api", gvr.Version}
+	}
+	if namespace != "" {
+		segments = append(segments, "namespaces", namespace)
+	}
+	segments = append(segments, gvr.Resource)
+
+	return client.Get().
+		AbsPath(path.Join(segments...)).
+		SetHeader("Accept", tableAcceptHeader).
+		Param("includeObject", string(metav1.IncludeMetadata)).
+		VersionedParams(&metav1.ListOptions{LabelSelector: labelSelector}, scheme.ParameterCodec)
+}
+
+// listTable lists resources as a table
+func listTable(ctx context.Context, client rest.Interface, gvr schema.GroupVersionResource, namespace, labelSelector string) (*ResourceTable, error) {
+	data, err := tableRequest(client, gvr, namespace, labelSelector).DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var table metav1.Table
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("failed to decode table: %w", err)
+	}
+	if table.Kind != "Table" {
+		return nil, fmt.Errorf("server did not render %s as a table", gvr.String())
+	}
+
+	rows, err := convertTableRows(table.Rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResourceTable{
+		Columns:         table.ColumnDefinitions,
+		Rows:            rows,
+		ResourceVersion: table.ResourceVersion,
+	}, nil
+}
+
+// streamTable watches table rows from a resource version until the watch ends.
+// Each event carries the rows it changed and the resource version to resume from.
+func streamTable(ctx context.Context, client rest.Interface, gvr schema.GroupVersionResource, namespace, labelSelector, resourceVersion string, handler func(eventType string, rows []TableRow, resourceVersion string)) error {
+	stream, err := tableRequest(client, gvr, namespace, labelSelector).
+		Param("watch", "true").
+		Param("resourceVersion", resourceVersion).
+		Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	decoder := json.NewDecoder(stream)
+	for {
+		var event metav1.WatchEvent
+		if err := decoder.Decode(&event); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		if watch.EventType(event.Type) == watch.Error {
+			var status metav1.Status
+			if err := json.Unmarshal(event.Object.Raw, &status); err != nil {
+				return fmt.Errorf("failed to decode watch error: %w", err)
+			}
+			return &apierrors.StatusError{ErrStatus: status}
+		}
+
+		var table metav1.Table
+		if err := json.Unmarshal(event.Object.Raw, &table); err != nil {
+			return fmt.Errorf("failed to decode table event: %w", err)
+		}
+
+		rows, err := convertTableRows(table.Rows)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		handler(event.Type, rows, rows[len(rows)-1].ResourceVersion)
+	}
+}
+
+// convertTableRows converts table rows with their object metadata to typed rows
+func convertTableRows(rows []metav1.TableRow) ([]TableRow, error) {
+	result := make([]TableRow, 0, len(rows))
+	for _, row := range rows {
+		var object metav1.PartialObjectMetadata
+		if len(row.Object.Raw) > 0 {
+			if err := json.Unmarshal(row.Object.Raw, &object); err != nil {
+				return nil, fmt.Errorf("failed to decode table row object: %w", err)
+			}
+		}
+
+		result = append(result, TableRow{
+			Namespace:       object.Namespace,
+			Name:            object.Name,
+			UID:             string(object.UID),
+			ResourceVersion: object.ResourceVersion,
+			Cells:           row.Cells,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,288 @@
+package informer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DefaultTreeMaxChildren is how many children of a tree node are returned unless configured otherwise
+const DefaultTreeMaxChildren = 20
+
+// maxOwnerDepth bounds owner and child walks, ownership chains are short in practice
+const maxOwnerDepth = 10
+
+// ResourceTreeNode is a resource in an ownership tree
+type ResourceTreeNode struct {
+	GVR       schema.GroupVersionResource `json:"gvr"`
+	Kind      string                      `json:"kind"`
+	Namespace string                      `json:"namespace,omitempty"`
+	Name      string                      `json:"name"`
+	UID       string                      `json:"uid"`
+	Status    string                      `json:"status"` // ready, progressing, failed, unknown
+	Message   string                      `json:"message,omitempty"`
+	Focus     bool                        `json:"focus,omitempty"` // the resource the tree was requested for
+	Children  []*ResourceTreeNode         `json:"children,omitempty"`
+	// MoreChildren is the number of children left out beyond the limit
+	MoreChildren int `json:"moreChildren,omitempty"`
+}
+
+// GetResourceTree returns the ownership tree of a resource, like kubectl tree. The
+// tree starts at the root owner found through ownerReferences and contains the
+// resources it owns in the informer caches of the cluster. Each node returns at
+// most maxChildren children, zero for DefaultTreeMaxChildren.
+func (im *InformerManager) GetResourceTree(clusterID string, gvr schema.GroupVersionResource, namespace, name string, maxChildren int) (*ResourceTreeNode, error) {
+	cluster, err := im.getCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	if maxChildren <= 0 {
+		maxChildren = DefaultTreeMaxChildren
+	}
+
+	obj, err := im.getObject(context.TODO(), cluster, gvr, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	focusUID := obj.GetUID()
+
+	// Walk up to the root owner, owners that no longer exist end the walk
+	rootGVR, root := gvr, obj
+	for depth := 0; depth < maxOwnerDepth; depth++ {
+		ownerGVR, owner, err := im.getOwner(context.TODO(), cluster, root)
+		if err != nil || owner == nil {
+			break
+		}
+		rootGVR, root = ownerGVR, owner
+	}
+
+	watchers := cluster.watcherSnapshot()
+	visited := map[types.UID]bool{}
+	return im.buildTreeNode(cluster, watchers, treeChild{gvr: rootGVR, kind: root.GetKind(), obj: root}, focusUID, maxChildren, visited, 0), nil
+}
+
+// treeChild is an object found in the informer caches while building a tree.
+// Objects of metadata-only watchers carry no kind, spec or status of their own.
+type treeChild struct {
+	gvr          schema.GroupVersionResource
+	kind         string
+	obj          *unstructured.Unstructured
+	metadataOnly bool
+}
+
+// buildTreeNode returns the tree node of an object with the children owned by it
+func (im *InformerManager) buildTreeNode(cluster *ClusterConnection, watchers []*ResourceWatcher, c treeChild, focusUID types.UID, maxChildren int, visited map[types.UID]bool, depth int) *ResourceTreeNode {
+	obj := c.obj
+	visited[obj.GetUID()] = true
+
+	// Metadata-only objects carry no status, they are not fetched one by one
+	// since a tree may hold many of them
+	status, message := StatusUnknown, ""
+	if !c.metadataOnly {
+		status, message = ResourceStatus(obj)
+	}
+	node := &ResourceTreeNode{
+		GVR:       c.gvr,
+		Kind:      c.kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		UID:       string(obj.GetUID()),
+		Status:    status,
+		Message:   message,
+		Focus:     obj.GetUID() == focusUID,
+	}
+	if depth >= maxOwnerDepth {
+		return node
+	}
+
+	// Children held by both a full and a metadata-only watcher use the full object
+	var children []treeChild
+	found := map[types.UID]int{}
+	for _, watcher := range watchers {
+		objects, err := watcher.byIndex(IndexOwnerUID, string(obj.GetUID()))
+		if err != nil {
+			continue
+		}
+		key := watcher.currentKey()
+		for _, item := range objects {
+			childObj, ok := item.(*unstructured.Unstructured)
+			if !ok || visited[childObj.GetUID()] {
+				continue
+			}
+			child := treeChild{gvr: key.GVR, kind: childObj.GetKind(), obj: childObj, metadataOnly: key.MetadataOnly}
+			if child.metadataOnly {
+				child.kind = resourceKind(cluster, child.gvr)
+			}
+			if i, ok := found[childObj.GetUID()]; ok {
+				if children[i].metadataOnly && !child.metadataOnly {
+					children[i] = child
+				}
+				continue
+			}
+			found[childObj.GetUID()] = len(children)
+			children = append(children, child)
+		}
+	}
+	for _, child := range children {
+		visited[child.obj.GetUID()] = true
+	}
+
+	sort.Slice(children, func(i, j int) bool {
+		a, b := children[i].obj, children[j].obj
+		if children[i].kind != children[j].kind {
+			return children[i].kind < children[j].kind
+		}
+		if a.GetNamespace() != b.GetNamespace() {
+			return a.GetNamespace() < b.GetNamespace()
+		}
+		return a.GetName() < b.GetName()
+	})
+
+	for i, child := range children {
+		if i >= maxChildren {
+			node.MoreChildren = len(children) - maxChildren
+			break
+		}
+		node.Children = append(node.Children, im.buildTreeNode(cluster, watchers, child, focusUID, maxChildren, visited, depth+1))
+	}
+	return node
+}
+
+// watcherSnapshot returns the running watchers of the cluster
+func (c *ClusterConnection) watcherSnapshot() []*ResourceWatcher {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	watchers := make([]*ResourceWatcher, 0, len(c.Informers))
+	for _, watcher := range c.Informers {
+		watchers = append(watchers, watcher)
+	}
+	return watchers
+}
+
+// getOwner returns the controlling owner of an object, or its first owner if none
+// controls it. It returns nil if the object has no owner or the owner is gone.
+func (im *InformerManager) getOwner(ctx context.Context, cluster *ClusterConnection, obj *unstructured.Unstructured) (schema.GroupVersionResource, *unstructured.Unstructured, error) {
+	ownerRef := controllerOf(obj)
+	if ownerRef == nil {
+		return schema.GroupVersionResource{}, nil, nil
+	}
+
+	mapping, err := ownerMapping(cluster, *ownerRef)
+	if err != nil {
+		return schema.GroupVersionResource{}, nil, err
+	}
+	gvr := mapping.Resource
+
+	// Owners are in the namespace of the object, or cluster-scoped
+	namespace := obj.GetNamespace()
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		namespace = ""
+	}
+
+	owner, err := im.getObject(ctx, cluster, gvr, namespace, ownerRef.Name)
+	if apierrors.IsNotFound(err) {
+		return gvr, nil, nil
+	}
+	if err != nil {
+		return gvr, nil, err
+	}
+	if owner.GetUID() != ownerRef.UID {
+		return gvr, nil, nil // Replaced by a new object with the same name
+	}
+	return gvr, owner, nil
+}
+
+// controllerOf returns the controller reference of an object, or its first owner reference
+func controllerOf(obj *unstructured.Unstructured) *metav1.OwnerReference {
+	ownerRefs := obj.GetOwnerReferences()
+	for i := range ownerRefs {
+		if ownerRefs[i].Controller != nil && *ownerRefs[i].Controller {
+			return &ownerRefs[i]
+		}
+	}
+	if len(ownerRefs) > 0 {
+		return &ownerRefs[0]
+	}
+	return nil
+}
+
+// ownerMapping resolves the resource type of an owner reference
+func ownerMapping(cluster *ClusterConnection, ownerRef metav1.OwnerReference) (*meta.RESTMapping, error) {
+	gvk := schema.FromAPIVersionAndKind(ownerRef.APIVersion, ownerRef.Kind)
+	mapping, err := cluster.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve owner kind %s: %w", gvk.String(), err)
+	}
+	return mapping, nil
+}
+
+// resourceKind returns the kind of a resource type, or an empty string if it is unknown
+func resourceKind(cluster *ClusterConnection, gvr schema.GroupVersionResource) string {
+	gvk, err := cluster.Mapper.KindFor(gvr)
+	if err != nil {
+		return ""
+	}
+	return gvk.Kind
+}
+
+// getObject returns an object from the informer caches, or from the apiserver if
+// no watcher holds the full object
+func (im *InformerManager) getObject(ctx context.Context, cluster *ClusterConnection, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	if obj := cluster.cachedObject(gvr, namespace, name); obj != nil {
+		return obj, nil
+	}
+	return cluster.Client.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// cachedObject returns an object held by a watcher of the cluster. Metadata-only
+// watchers are skipped, their objects have no kind, spec or status.
+func (c *ClusterConnection) cachedObject(gvr schema.GroupVersionResource, namespace, name string) *unstructured.Unstructured {
+	return c.heldObject(gvr, namespace, name, false)
+}
+
+// holdsObject reports whether any watcher of the cluster holds an object
+func (c *ClusterConnection) holdsObject(gvr schema.GroupVersionResource, namespace, name string) bool {
+	return c.heldObject(gvr, namespace, name, true) != nil
+}
+
+// heldObject returns an object held by a watcher of the cluster, full objects are
+// preferred over metadata-only ones if those are included
+func (c *ClusterConnection) heldObject(gvr schema.GroupVersionResource, namespace, name string, includeMetadataOnly bool) *unstructured.Unstructured {
+	storeKey := name
+	if namespace != "" {
+		storeKey = namespace + "/" + name
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var metadataOnly *unstructured.Unstructured
+	for key, watcher := range c.Informers {
+		if key.GVR != gvr || (key.MetadataOnly && !includeMetadataOnly) {
+			continue
+		}
+		for _, informer := range watcher.Informers() {
+			item, exists, err := informer.GetStore().GetByKey(storeKey)
+			if err != nil || !exists {
+				continue
+			}
+			obj, ok := item.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+			if !key.MetadataOnly {
+				return obj
+			}
+			metadataOnly = obj
+		}
+	}
+	return metadataOnly
+}
@@ -0,0 +1,370 @@
+package informer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+// WatcherKey identifies a resource watcher by resource type and scope
+type WatcherKey struct {
+	GVR schema.GroupVersionResource `json:"gvr"`
+	// Namespaces is the sorted, comma separated namespace set, empty for all namespaces
+	Namespaces    string `json:"namespaces,omitempty"`
+	LabelSelector string `json:"labelSelector,omitempty"`
+	FieldSelector string `json:"fieldSelector,omitempty"`
+	// MetadataOnly watches object metadata only, full objects have to be fetched on demand
+	MetadataOnly bool `json:"metadataOnly,omitempty"`
+}
+
+// NewWatcherKey creates a watcher key, no namespaces means all namespaces
+func NewWatcherKey(gvr schema.GroupVersionResource, namespaces ...string) WatcherKey {
+	return WatcherKey{
+		GVR:        gvr,
+		Namespaces: joinNamespaces(namespaces),
+	}
+}
+
+// WithSelectors returns a copy of the key restricted by label and field selectors.
+// Selectors are normalized so equivalent selectors share a watcher.
+func (k WatcherKey) WithSelectors(labelSelector, fieldSelector string) (WatcherKey, error) {
+	parsedLabels, err := labels.Parse(labelSelector)
+	if err != nil {
+		return k, fmt.Errorf("invalid label selector %q: %w", labelSelector, err)
+	}
+
+	parsedFields, err := fields.ParseSelector(fieldSelector)
+	if err != nil {
+		return k, fmt.Errorf("invalid field selector %q: %w", fieldSelector, err)
+	}
+
+	k.LabelSelector = parsedLabels.String()
+	k.FieldSelector = parsedFields.String()
+	return k, nil
+}
+
+// NamespaceList returns the namespaces of the key, nil for all namespaces
+func (k WatcherKey) NamespaceList() []string {
+	if k.Namespaces == "" {
+		return nil
+	}
+	return strings.Split(k.Namespaces, ",")
+}
+
+// AddNamespace returns the key widened by a namespace, only namespace-scoped keys can be widened
+func (k WatcherKey) AddNamespace(namespace string) (WatcherKey, error) {
+	if namespace == "" {
+		return k, fmt.Errorf("namespace must not be empty")
+	}
+	if k.Namespaces == "" {
+		return k, fmt.Errorf("watcher for %s already watches all namespaces", k.String())
+	}
+
+	k.Namespaces = joinNamespaces(append(k.NamespaceList(), namespace))
+	return k, nil
+}
+
+// RemoveNamespace returns the key narrowed by a namespace, the last namespace cannot be removed
+func (k WatcherKey) RemoveNamespace(namespace string) (WatcherKey, error) {
+	var remaining []string
+	for _, watched := range k.NamespaceList() {
+		if watched != namespace {
+			remaining = append(remaining, watched)
+		}
+	}
+
+	if len(remaining) == len(k.NamespaceList()) {
+		return k, fmt.Errorf("namespace %s is not watched by %s", namespace, k.String())
+	}
+	if len(remaining) == 0 {
+		return k, fmt.Errorf("cannot remove the last namespace of %s, remove the watcher instead", k.String())
+	}
+
+	k.Namespaces = joinNamespaces(remaining)
+	return k, nil
+}
+
+// String returns a readable representation of the key
+func (k WatcherKey) String() string {
+	result := k.GVR.String()
+	if k.Namespaces != "" {
+		result += ", Namespaces=" + k.Namespaces
+	}
+	if k.LabelSelector != "" {
+		result += ", LabelSelector=" + k.LabelSelector
+	}
+	if k.FieldSelector != "" {
+		result += ", FieldSelector=" + k.FieldSelector
+	}
+	if k.MetadataOnly {
+		result += ", MetadataOnly"
+	}
+	return result
+}
+
+// filtered reports whether selectors restrict the key. Filtered watchers see objects
+// leave their scope as DELETED when they stop matching.
+func (k WatcherKey) filtered() bool {
+	return k.LabelSelector != "" || k.FieldSelector != ""
+}
+
+// tweakListOptions applies the selectors of the key to list and watch requests
+func (k WatcherKey) tweakListOptions(options *metav1.ListOptions) {
+	options.LabelSelector = k.LabelSelector
+	options.FieldSelector = k.FieldSelector
+}
+
+// matches reports whether an object is selected by the selectors of the key
+func (k WatcherKey) matches(obj *unstructured.Unstructured) bool {
+	labelSelector, err := labels.Parse(k.LabelSelector)
+	if err != nil {
+		return false
+	}
+
+	fieldSelector, err := fields.ParseSelector(k.FieldSelector)
+	if err != nil {
+		return false
+	}
+
+	return selectorsMatch(obj, labelSelector, fieldSelector)
+}
+
+// selectorsMatch reports whether an object is selected by label and field selectors
+func selectorsMatch(obj *unstructured.Unstructured, labelSelector labels.Selector, fieldSelector fields.Selector) bool {
+	if !labelSelector.Matches(labels.Set(obj.GetLabels())) {
+		return false
+	}
+	if fieldSelector.Empty() {
+		return true
+	}
+
+	// Only the fields referenced by the selector are looked up
+	fieldSet := fields.Set{}
+	for _, requirement := range fieldSelector.Requirements() {
+		value, found, _ := unstructured.NestedFieldNoCopy(obj.Object, strings.Split(requirement.Field, ".")...)
+		if found {
+			fieldSet[requirement.Field] = fmt.Sprint(value)
+		}
+	}
+	return fieldSelector.Matches(fieldSet)
+}
+
+// joinNamespaces returns the canonical form of a namespace set
+func joinNamespaces(namespaces []string) string {
+	set := make(map[string]struct{}, len(namespaces))
+	for _, namespace := range namespaces {
+		if namespace != "" {
+			set[namespace] = struct{}{}
+		}
+	}
+
+	result := make([]string, 0, len(set))
+	for namespace := range set {
+		result = append(result, namespace)
+	}
+	sort.Strings(result)
+
+	return strings.Join(result, ",")
+}
+
+// ResourceWatcher owns the informers of one watcher key and the lifecycle of
+// their watch connections. A watcher scoped to a namespace set runs one
+// namespace-filtered informer per namespace, otherwise a single cluster-wide one.
+type ResourceWatcher struct {
+	Key       WatcherKey
+	informers map[string]*scopedInformer // namespace -> informer, "" for all namespaces
+	stats     watcherStats
+	ctx       context.Context
+	cancel    context.CancelFunc
+	mu        sync.RWMutex
+}
+
+// scopedInformer is an informer for a single namespace of a watcher
+type scopedInformer struct {
+	informer  cache.SharedIndexInformer
+	cancel    context.CancelFunc
+	done      chan struct{}
+	lastError *WatchError // cleared once the informer lists or watches again
+}
+
+// newResourceWatcher creates a watcher that stops when it is stopped itself or the parent context is done
+func newResourceWatcher(parent context.Context, key WatcherKey) *ResourceWatcher {
+	ctx, cancel := context.WithCancel(parent)
+
+	return &ResourceWatcher{
+		Key:       key,
+		informers: make(map[string]*scopedInformer),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// startInformer builds and runs the informer for a namespace. The context passed
+// to build is cancelled once the informer is stopped.
+func (w *ResourceWatcher) startInformer(namespace string, build func(ctx context.Context) cache.SharedIndexInformer) cache.SharedIndexInformer {
+	ctx, cancel := context.WithCancel(w.ctx)
+	scoped := &scopedInformer{
+		informer: build(ctx),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+
+	w.mu.Lock()
+	w.informers[namespace] = scoped
+	w.mu.Unlock()
+
+	go func() {
+		defer close(scoped.done)
+		scoped.informer.Run(ctx.Done())
+	}()
+
+	return scoped.informer
+}
+
+// stopInformer stops the informer for a namespace and waits for it to exit
+func (w *ResourceWatcher) stopInformer(namespace string) cache.SharedIndexInformer {
+	w.mu.Lock()
+	scoped, exists := w.informers[namespace]
+	delete(w.informers, namespace)
+	w.mu.Unlock()
+
+	if !exists {
+		return nil
+	}
+
+	scoped.cancel()
+	<-scoped.done
+	return scoped.informer
+}
+
+// Stop closes all watch connections and waits for the informers to exit
+func (w *ResourceWatcher) Stop() {
+	w.cancel()
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	for _, scoped := range w.informers {
+		<-scoped.done
+	}
+}
+
+// Stopped reports whether the watcher has been stopped
+func (w *ResourceWatcher) Stopped() bool {
+	return w.ctx.Err() != nil
+}
+
+// Informers returns the running informers by namespace, "" for all namespaces
+func (w *ResourceWatcher) Informers() map[string]cache.SharedIndexInformer {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	result := make(map[string]cache.SharedIndexInformer, len(w.informers))
+	for namespace, scoped := range w.informers {
+		result[namespace] = scoped.informer
+	}
+	return result
+}
+
+// HasSynced reports whether all informers of the watcher have synced
+func (w *ResourceWatcher) HasSynced() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	for _, scoped := range w.informers {
+		if !scoped.informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// setKey changes the key of a rescoped watcher, the caller holds the cluster lock
+func (w *ResourceWatcher) setKey(key WatcherKey) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.Key = key
+}
+
+// currentKey returns the key of the watcher, rescoped watchers change it in place
+func (w *ResourceWatcher) currentKey() WatcherKey {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.Key
+}
+
+// setError records the last error of the informer for a namespace, it reports
+// false if that informer is no longer running
+func (w *ResourceWatcher) setError(namespace string, informer cache.SharedIndexInformer, watchError *WatchError) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	scoped, exists := w.informers[namespace]
+	if !exists || scoped.informer != informer {
+		return false
+	}
+	watchError.Key = w.Key
+	scoped.lastError = watchError
+	return true
+}
+
+// clearError clears the last error of the informer for a namespace
+func (w *ResourceWatcher) clearError(namespace string, informer cache.SharedIndexInformer) {
+	// Called for every event, most informers have no error to clear
+	if !w.hasError(namespace) {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if scoped, exists := w.informers[namespace]; exists && scoped.informer == informer {
+		scoped.lastError = nil
+	}
+}
+
+// hasError reports whether the informer for a namespace has a recorded error
+func (w *ResourceWatcher) hasError(namespace string) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	scoped, exists := w.informers[namespace]
+	return exists && scoped.lastError != nil
+}
+
+// Errors returns the last errors of the informers that are failing
+func (w *ResourceWatcher) Errors() []WatchError {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var result []WatchError
+	for _, scoped := range w.informers {
+		if scoped.lastError != nil {
+			result = append(result, *scoped.lastError)
+		}
+	}
+	return result
+}
+
+// List returns all objects currently held by the watcher's informers
+func (w *ResourceWatcher) List() []any {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var result []any
+	for _, scoped := range w.informers {
+		result = append(result, scoped.informer.GetStore().List()...)
+	}
+	return result
+}
@@ -0,0 +1,97 @@
+package informer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/tools/cache"
+)
+
+// WatchError is a list or watch failure of a running informer
+type WatchError struct {
+	ClusterID string     `json:"clusterId"`
+	Key       WatcherKey `json:"key"`
+	Namespace string     `json:"namespace,omitempty"` // the failing namespace of a scoped watcher
+	Type      string     `json:"type"`                // unauthorized, forbidden, gone, notfound, network, timeout, unknown
+	Message   string     `json:"message"`
+	Timestamp time.Time  `json:"timestamp"`
+}
+
+// SetWatchErrorHandler sets the handler called whenever an informer fails to list or watch
+func (im *InformerManager) SetWatchErrorHandler(handler func(WatchError)) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.watchErrorHandler = handler
+}
+
+// recordWatchError records a failure of the informer of a watcher namespace and
+// passes it to the watch error handler. Failures of informers that were replaced
+// or stopped meanwhile are dropped.
+func (im *InformerManager) recordWatchError(clusterID string, watcher *ResourceWatcher, namespace string, informer cache.SharedIndexInformer, errorType string, err error) {
+	// The key is filled in under the watcher lock, watchers are rescoped in place
+	watchError := WatchError{
+		ClusterID: clusterID,
+		Namespace: namespace,
+		Type:      errorType,
+		Message:   err.Error(),
+		Timestamp: time.Now(),
+	}
+	if !watcher.setError(namespace, informer, &watchError) {
+		return
+	}
+
+	im.emitWatchError(watchError)
+}
+
+// reportAccessError passes a failed access check of a watcher namespace to the
+// watch error handler, no informer is started for the namespace
+func (im *InformerManager) reportAccessError(clusterID string, key WatcherKey, namespace string, err error) {
+	im.emitWatchError(WatchError{
+		ClusterID: clusterID,
+		Key:       key,
+		Namespace: namespace,
+		Type:      classifyWatchError(err),
+		Message:   err.Error(),
+		Timestamp: time.Now(),
+	})
+}
+
+// emitWatchError logs a watch error and passes it to the watch error handler
+func (im *InformerManager) emitWatchError(watchError WatchError) {
+	fmt.Printf("Warning: watch of %s in cluster %s failed (%s): %s\n", watchError.Key.String(), watchError.ClusterID, watchError.Type, watchError.Message)
+
+	im.mu.RLock()
+	handler := im.watchErrorHandler
+	im.mu.RUnlock()
+
+	if handler != nil {
+		handler(watchError)
+	}
+}
+
+// classifyWatchError returns the type of a list or watch failure
+func classifyWatchError(err error) string {
+	var netErr net.Error
+	switch {
+	case apierrors.IsUnauthorized(err):
+		return "unauthorized"
+	case apierrors.IsForbidden(err):
+		return "forbidden"
+	case apierrors.IsResourceExpired(err) || apierrors.IsGone(err):
+		return "gone"
+	case apierrors.IsNotFound(err):
+		// The resource type was removed, e.g. its CRD was deleted
+		return "notfound"
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF), errors.As(err, &netErr),
+		utilnet.IsConnectionRefused(err), utilnet.IsConnectionReset(err), utilnet.IsProbableEOF(err):
+		return "network"
+	default:
+		return "unknown"
+	}
+}
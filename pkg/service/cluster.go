@@ -5,13 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"ksight/pkg/informer"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/discovery"
 )
 
 // EventEmitter interface for abstracting event emission
@@ -51,6 +52,17 @@ type ClusterService struct {
 	informerManager *informer.InformerManager
 	dataDir         string
 	eventEmitter    EventEmitter
+	events          *EventPipeline
+
+	// Watchers are shared between subscriptions and stopped once idle
+	subscriptions      map[string]watcherRef
+	watchers           map[watcherRef]*sharedWatcher
+	nextSubscriptionID int
+	watcherGracePeriod time.Duration
+	subscriptionMu     sync.Mutex
+
+	tableWatches  *watchRegistry[*informer.TableWatch]
+	eventsWatches *watchRegistry[*informer.EventsWatch]
 }
 
 // ClusterInfo represents cluster information for frontend
@@ -62,15 +74,56 @@ type ClusterInfo struct {
 	Status    string `json:"status"`
 	LastError string `json:"lastError,omitempty"`
 	IsPinned  bool   `json:"isPinned"`
+
+	ResyncPeriodSeconds int    `json:"resyncPeriodSeconds"`
+	SuppressedEvents    uint64 `json:"suppressedEvents"`
 }
 
 // ResourceWatchRequest represents a request to watch resources
 type ResourceWatchRequest struct {
-	ClusterID string `json:"clusterId"`
-	Group     string `json:"group"`
-	Version   string `json:"version"`
-	Resource  string `json:"resource"`
-	Namespace string `json:"namespace,omitempty"`
+	ClusterID     string   `json:"clusterId"`
+	Group         string   `json:"group"`
+	Version       string   `json:"version"`
+	Resource      string   `json:"resource"`
+	Namespace     string   `json:"namespace,omitempty"`
+	Namespaces    []string `json:"namespaces,omitempty"`
+	LabelSelector string   `json:"labelSelector,omitempty"`
+	FieldSelector string   `json:"fieldSelector,omitempty"`
+	MetadataOnly  bool     `json:"metadataOnly,omitempty"`
+}
+
+// watcherKey returns the informer watcher key for the request, no namespaces means all namespaces
+func (r ResourceWatchRequest) watcherKey() (informer.WatcherKey, error) {
+	gvr := schema.GroupVersionResource{
+		Group:    r.Group,
+		Version:  r.Version,
+		Resource: r.Resource,
+	}
+
+	key := informer.NewWatcherKey(gvr, append([]string{r.Namespace}, r.Namespaces...)...)
+	key.MetadataOnly = r.MetadataOnly
+	return key.WithSelectors(r.LabelSelector, r.FieldSelector)
+}
+
+// withWatcherKey returns a copy of the request scoped to the namespaces and selectors of the key
+func (r ResourceWatchRequest) withWatcherKey(key informer.WatcherKey) ResourceWatchRequest {
+	r.Namespace = ""
+	r.Namespaces = key.NamespaceList()
+	r.LabelSelector = key.LabelSelector
+	r.FieldSelector = key.FieldSelector
+	r.MetadataOnly = key.MetadataOnly
+	return r
+}
+
+// newResourceWatchRequest returns the request describing a watcher key
+func newResourceWatchRequest(clusterID string, key informer.WatcherKey) ResourceWatchRequest {
+	request := ResourceWatchRequest{
+		ClusterID: clusterID,
+		Group:     key.GVR.Group,
+		Version:   key.GVR.Version,
+		Resource:  key.GVR.Resource,
+	}
+	return request.withWatcherKey(key)
 }
 
 // NewClusterService creates a new cluster service
@@ -81,20 +134,48 @@ func NewClusterService(ctx context.Context) *ClusterService {
 	os.MkdirAll(dataDir, 0755)
 
 	cs := &ClusterService{
-		ctx:          ctx,
-		dataDir:      dataDir,
-		eventEmitter: detectEnvironment(ctx),
+		ctx:                ctx,
+		dataDir:            dataDir,
+		eventEmitter:       detectEnvironment(ctx),
+		subscriptions:      make(map[string]watcherRef),
+		watchers:           make(map[watcherRef]*sharedWatcher),
+		watcherGracePeriod: DefaultWatcherGracePeriod,
+		tableWatches:       newWatchRegistry[*informer.TableWatch]("table"),
+		eventsWatches:      newWatchRegistry[*informer.EventsWatch]("events"),
 	}
+	cs.events = NewEventPipeline(cs.eventEmitter, DefaultEventPipelineConfig())
 
 	// Create informer manager with event handler
 	manager := informer.NewInformerManager(
 		filepath.Join(dataDir, "last_watch_resource_versions.json"),
 		func(event informer.Event) {
-			// Emit event to frontend
-			cs.eventEmitter.Emit("resource:event", event)
+			// Batch events for the frontend
+			cs.events.Push(event)
 		},
 	)
 
+	// Emit cluster updated events on connection status changes
+	manager.SetStatusHandler(func(clusterID string) {
+		if clusterInfo, exists := cs.GetClusters()[clusterID]; exists {
+			cs.eventEmitter.Emit("cluster:updated", clusterInfo)
+		}
+	})
+
+	// Let the UI reload resource types when CRDs or APIServices change
+	manager.SetResourceTypesHandler(func(clusterID string) {
+		cs.eventEmitter.Emit("resourcetypes:changed", clusterID)
+	})
+
+	// Show which resource types are failing
+	manager.SetWatchErrorHandler(func(watchError informer.WatchError) {
+		cs.eventEmitter.Emit("watcher:error", newWatcherErrorEvent(watchError))
+	})
+
+	// Keep workload columns current when owners change
+	manager.SetRootControllerHandler(func(clusterID string, results []informer.RootControllerResult) {
+		cs.eventEmitter.Emit("rootcontrollers:updated", RootControllersEvent{ClusterID: clusterID, Results: results})
+	})
+
 	cs.informerManager = manager
 	return cs
 }
@@ -123,6 +204,10 @@ func (cs *ClusterService) RemoveCluster(clusterID string) error {
 		return err
 	}
 
+	cs.dropClusterSubscriptions(clusterID)
+	cs.tableWatches.stopCluster(clusterID)
+	cs.eventsWatches.stopCluster(clusterID)
+
 	// Emit cluster removed event
 	cs.eventEmitter.Emit("cluster:removed", clusterID)
 
@@ -135,14 +220,18 @@ func (cs *ClusterService) GetClusters() map[string]ClusterInfo {
 	result := make(map[string]ClusterInfo)
 
 	for id, cluster := range clusters {
+		status, lastError := cluster.Health()
 		result[id] = ClusterInfo{
 			ID:        cluster.ID,
 			Name:      cluster.Name,
 			Context:   cluster.Context,
 			Server:    cluster.Server,
-			Status:    cluster.Status,
-			LastError: cluster.LastError,
+			Status:    status,
+			LastError: lastError,
 			IsPinned:  cluster.IsPinned,
+
+			ResyncPeriodSeconds: int(cluster.ResyncPeriod / time.Second),
+			SuppressedEvents:    cluster.SuppressedEvents.Load(),
 		}
 	}
 
@@ -160,55 +249,143 @@ func (cs *ClusterService) ToggleClusterPin(clusterID string) error {
 	cluster.IsPinned = !cluster.IsPinned
 
 	// Emit cluster updated event
+	status, lastError := cluster.Health()
 	clusterInfo := ClusterInfo{
 		ID:        cluster.ID,
 		Name:      cluster.Name,
 		Context:   cluster.Context,
 		Server:    cluster.Server,
-		Status:    cluster.Status,
-		LastError: cluster.LastError,
+		Status:    status,
+		LastError: lastError,
 		IsPinned:  cluster.IsPinned,
+
+		ResyncPeriodSeconds: int(cluster.ResyncPeriod / time.Second),
+		SuppressedEvents:    cluster.SuppressedEvents.Load(),
 	}
 	cs.eventEmitter.Emit("cluster:updated", clusterInfo)
 
 	return nil
 }
 
-// AddResourceWatcher adds a resource watcher for a cluster
-func (cs *ClusterService) AddResourceWatcher(request ResourceWatchRequest) error {
-	gvr := schema.GroupVersionResource{
-		Group:    request.Group,
-		Version:  request.Version,
-		Resource: request.Resource,
+// AddResourceWatcher subscribes to a resource watcher for a cluster and returns the
+// subscription ID. Subscriptions with the same scope share one watcher.
+func (cs *ClusterService) AddResourceWatcher(request ResourceWatchRequest) (string, error) {
+	key, err := request.watcherKey()
+	if err != nil {
+		return "", err
 	}
 
-	err := cs.informerManager.AddResourceWatcher(request.ClusterID, gvr, request.Namespace)
-	if err != nil {
-		return err
+	cs.subscriptionMu.Lock()
+	defer cs.subscriptionMu.Unlock()
+
+	ref := watcherRef{clusterID: request.ClusterID, key: key}
+	if err := cs.acquireWatcher(ref, request); err != nil {
+		return "", err
 	}
 
-	// Emit watcher added event
-	cs.eventEmitter.Emit("watcher:added", request)
+	cs.nextSubscriptionID++
+	subscriptionID := fmt.Sprintf("subscription_%d", cs.nextSubscriptionID)
+	cs.subscriptions[subscriptionID] = ref
+
+	return subscriptionID, nil
+}
+
+// RemoveResourceWatcher removes a subscription, the watcher is stopped after the
+// grace period once no subscription uses it
+func (cs *ClusterService) RemoveResourceWatcher(subscriptionID string) error {
+	cs.subscriptionMu.Lock()
+	defer cs.subscriptionMu.Unlock()
+
+	ref, exists := cs.subscriptions[subscriptionID]
+	if !exists {
+		return fmt.Errorf("subscription %s not found", subscriptionID)
+	}
+
+	delete(cs.subscriptions, subscriptionID)
+	cs.releaseWatcher(ref)
 
 	return nil
 }
 
-// RemoveResourceWatcher removes a resource watcher
-func (cs *ClusterService) RemoveResourceWatcher(request ResourceWatchRequest) error {
-	gvr := schema.GroupVersionResource{
-		Group:    request.Group,
-		Version:  request.Version,
-		Resource: request.Resource,
+// AddWatcherNamespace adds a namespace to the namespace-scoped watcher of a subscription
+func (cs *ClusterService) AddWatcherNamespace(subscriptionID, namespace string) (ResourceWatchRequest, error) {
+	cs.subscriptionMu.Lock()
+	defer cs.subscriptionMu.Unlock()
+
+	ref, exists := cs.subscriptions[subscriptionID]
+	if !exists {
+		return ResourceWatchRequest{}, fmt.Errorf("subscription %s not found", subscriptionID)
 	}
 
-	err := cs.informerManager.RemoveResourceWatcher(request.ClusterID, gvr)
+	newKey, err := ref.key.AddNamespace(namespace)
 	if err != nil {
-		return err
+		return cs.watchers[ref].request, err
 	}
 
-	// Emit watcher removed event
-	cs.eventEmitter.Emit("watcher:removed", request)
+	return cs.rescopeSubscription(subscriptionID, newKey, func(clusterID string, key informer.WatcherKey) (informer.WatcherKey, error) {
+		return cs.informerManager.AddWatcherNamespace(clusterID, key, namespace)
+	})
+}
+
+// RemoveWatcherNamespace removes a namespace from the namespace-scoped watcher of a subscription
+func (cs *ClusterService) RemoveWatcherNamespace(subscriptionID, namespace string) (ResourceWatchRequest, error) {
+	cs.subscriptionMu.Lock()
+	defer cs.subscriptionMu.Unlock()
 
+	ref, exists := cs.subscriptions[subscriptionID]
+	if !exists {
+		return ResourceWatchRequest{}, fmt.Errorf("subscription %s not found", subscriptionID)
+	}
+
+	newKey, err := ref.key.RemoveNamespace(namespace)
+	if err != nil {
+		return cs.watchers[ref].request, err
+	}
+
+	return cs.rescopeSubscription(subscriptionID, newKey, func(clusterID string, key informer.WatcherKey) (informer.WatcherKey, error) {
+		return cs.informerManager.RemoveWatcherNamespace(clusterID, key, namespace)
+	})
+}
+
+// AckResourceEvents acknowledges the resource events the frontend has processed
+func (cs *ClusterService) AckResourceEvents(sequence uint64) {
+	cs.events.Ack(sequence)
+}
+
+// SetEventEncoding sets whether updated objects are sent in full or as patches
+func (cs *ClusterService) SetEventEncoding(encoding string) error {
+	switch EventEncoding(encoding) {
+	case EventEncodingFull, EventEncodingPatch:
+	default:
+		return fmt.Errorf("unknown event encoding %q", encoding)
+	}
+
+	cs.events.SetEncoding(EventEncoding(encoding))
+	return nil
+}
+
+// ResyncResourceEvents sends the current objects of a resource type in full, for
+// objects whose patches arrived without the frontend holding their base. Objects
+// are given as namespace/name, or name for cluster-scoped resources.
+func (cs *ClusterService) ResyncResourceEvents(clusterID, group, version, resource string, metadataOnly bool, objects []string) error {
+	gvr := schema.GroupVersionResource{
+		Group:    group,
+		Version:  version,
+		Resource: resource,
+	}
+
+	for _, object := range objects {
+		namespace, name, found := strings.Cut(object, "/")
+		if !found {
+			namespace, name = "", object
+		}
+
+		event, err := cs.informerManager.SnapshotEvent(clusterID, gvr, metadataOnly, namespace, name)
+		if err != nil {
+			return err
+		}
+		cs.events.Resync(*event)
+	}
 	return nil
 }
 
@@ -268,43 +445,37 @@ func (cs *ClusterService) WatchDefaultKubeconfig() error {
 
 // GetResourceTypes returns available resource types for a cluster
 func (cs *ClusterService) GetResourceTypes(clusterID string) ([]schema.GroupVersionResource, error) {
-	clusters := cs.informerManager.GetClusters()
-	cluster, exists := clusters[clusterID]
-	if !exists {
-		return nil, fmt.Errorf("cluster %s not found", clusterID)
-	}
-
-	// Create discovery client from config
-	// This is a simplified implementation - in practice, you'd want to
-	// cache this information and refresh it periodically
-	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cluster.Config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create discovery client: %w", err)
-	}
+	return cs.informerManager.GetResourceTypes(clusterID)
+}
 
-	resourceLists, err := discoveryClient.ServerPreferredResources()
-	if err != nil {
-		return nil, fmt.Errorf("failed to discover resources: %w", err)
-	}
+// GetAPIResources returns the resource types of a cluster with their kind, scope,
+// verbs, short names, categories and subresources. Groups that failed to resolve,
+// like an unavailable metrics server, are reported next to the resolved ones.
+func (cs *ClusterService) GetAPIResources(clusterID string) (*informer.APIResources, error) {
+	return cs.informerManager.GetAPIResources(clusterID)
+}
 
-	var gvrs []schema.GroupVersionResource
-	for _, resourceList := range resourceLists {
-		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
-		if err != nil {
-			continue
-		}
+// ResolveResource resolves a resource name, short name or kind to its resource type,
+// e.g. "po", "deploy", "Deployment" or "deployments.v1.apps"
+func (cs *ClusterService) ResolveResource(clusterID, name string) (*informer.ResolvedResource, error) {
+	return cs.informerManager.ResolveResource(clusterID, name)
+}
 
-		for _, resource := range resourceList.APIResources {
-			gvr := schema.GroupVersionResource{
-				Group:    gv.Group,
-				Version:  gv.Version,
-				Resource: resource.Name,
-			}
-			gvrs = append(gvrs, gvr)
-		}
+// SetResyncPeriod sets the informer resync period of a cluster in seconds, zero disables
+// resyncs. With a resource the period only applies to that resource type and a negative
+// period restores the cluster period.
+func (cs *ClusterService) SetResyncPeriod(clusterID string, group, version, resource string, seconds int) error {
+	period := time.Duration(seconds) * time.Second
+	if resource == "" {
+		return cs.informerManager.SetResyncPeriod(clusterID, period)
 	}
 
-	return gvrs, nil
+	gvr := schema.GroupVersionResource{
+		Group:    group,
+		Version:  version,
+		Resource: resource,
+	}
+	return cs.informerManager.SetResourceResyncPeriod(clusterID, gvr, period)
 }
 
 // GetResourceWithSensitivity retrieves a resource, handling sensitive data appropriately
@@ -341,6 +512,26 @@ func (cs *ClusterService) GetResourceWithSensitivity(clusterID string, group, ve
 	}
 }
 
+// FetchResource gets the full object from the API server, used to load the details of
+// objects watched metadata-only
+func (cs *ClusterService) FetchResource(clusterID string, group, version, resource, namespace, name string) (map[string]any, error) {
+	gvr := schema.GroupVersionResource{
+		Group:    group,
+		Version:  version,
+		Resource: resource,
+	}
+
+	obj, isSensitive, err := cs.informerManager.FetchResource(clusterID, gvr, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := obj.Object
+	result["_sensitive"] = isSensitive
+
+	return result, nil
+}
+
 // GetCacheStats returns statistics about the resource cache
 func (cs *ClusterService) GetCacheStats() (map[string]int, error) {
 	stats, err := cs.informerManager.GetCacheStats()
@@ -356,6 +547,102 @@ func (cs *ClusterService) CleanOldCache(maxAgeHours int) error {
 	return cs.informerManager.CleanOldCache(maxAge)
 }
 
+// ResourceQuery is a query over the objects held by the watchers of a cluster
+type ResourceQuery struct {
+	ClusterID string `json:"clusterId"`
+	informer.ResourceQuery
+}
+
+// ListResources pages through watched objects with selectors and sorting, reading
+// from the informer caches instead of the apiserver
+func (cs *ClusterService) ListResources(query ResourceQuery) (*informer.ResourcePage, error) {
+	return cs.informerManager.ListResources(query.ClusterID, query.ResourceQuery)
+}
+
+// RegisterIndexer adds a JSONPath index, like {.spec.serviceAccountName}, to the
+// informers of a resource type. Queries select its values by the index name.
+func (cs *ClusterService) RegisterIndexer(group, version, resource, name, jsonPath string) error {
+	gvr := schema.GroupVersionResource{
+		Group:    group,
+		Version:  version,
+		Resource: resource,
+	}
+
+	return cs.informerManager.RegisterIndexer(gvr, name, jsonPath)
+}
+
+// GetDependencies returns the config maps, secrets, persistent volume claims and
+// service account a pod or workload uses
+func (cs *ClusterService) GetDependencies(clusterID string, group, version, resource, namespace, name string) ([]informer.Dependency, error) {
+	gvr := schema.GroupVersionResource{
+		Group:    group,
+		Version:  version,
+		Resource: resource,
+	}
+
+	return cs.informerManager.GetDependencies(clusterID, gvr, namespace, name)
+}
+
+// GetDependents returns the watched pods and workloads using a config map, secret,
+// persistent volume claim or service account, e.g. to warn before deleting it
+func (cs *ClusterService) GetDependents(clusterID, kind, namespace, name string) ([]informer.Dependent, error) {
+	return cs.informerManager.GetDependents(clusterID, kind, namespace, name)
+}
+
+// GetNetworkingFor returns the services selecting a pod, the endpoint slices holding
+// its IPs and the ingresses and HTTP routes sending to those services, as far as
+// they are watched
+func (cs *ClusterService) GetNetworkingFor(clusterID, namespace, name string) (*informer.PodNetworking, error) {
+	return cs.informerManager.GetNetworkingFor(clusterID, namespace, name)
+}
+
+// RootControllersEvent is emitted as "rootcontrollers:updated" when the root
+// controllers of resolved objects change
+type RootControllersEvent struct {
+	ClusterID string                          `json:"clusterId"`
+	Results   []informer.RootControllerResult `json:"results"`
+}
+
+// ResolveRootControllers returns the root controllers of objects, like the Deployment
+// or CronJob of a pod, in the order of the objects
+func (cs *ClusterService) ResolveRootControllers(clusterID string, objects []informer.ObjectReference) ([]informer.RootControllerResult, error) {
+	return cs.informerManager.ResolveRootControllers(clusterID, objects)
+}
+
+// SetExcludedOwnerKinds sets the owner kinds root controllers are not resolved through,
+// like "ReplicaSet" or "Rollout.argoproj.io"
+func (cs *ClusterService) SetExcludedOwnerKinds(kinds []string) {
+	cs.informerManager.SetExcludedOwnerKinds(kinds)
+}
+
+// GetResourceTree returns the ownership tree around a resource, from its root owner
+// down to the resources it owns, with their readiness. Nodes list at most maxChildren
+// children and count the rest, zero for the default limit.
+func (cs *ClusterService) GetResourceTree(clusterID string, group, version, resource, namespace, name string, maxChildren int) (*informer.ResourceTreeNode, error) {
+	gvr := schema.GroupVersionResource{
+		Group:    group,
+		Version:  version,
+		Resource: resource,
+	}
+
+	return cs.informerManager.GetResourceTree(clusterID, gvr, namespace, name, maxChildren)
+}
+
+// QueryEventHistory returns the recorded Kubernetes events of a cluster, including
+// those the cluster has already deleted
+func (cs *ClusterService) QueryEventHistory(query informer.EventHistoryQuery) ([]informer.KubernetesEvent, error) {
+	return cs.informerManager.QueryEventHistory(query)
+}
+
+// SetEventRetention sets how many hours and how many events the event history keeps,
+// zero keeps all
+func (cs *ClusterService) SetEventRetention(maxAgeHours, maxEvents int) error {
+	return cs.informerManager.SetEventRetention(informer.EventRetention{
+		MaxAge:    time.Duration(maxAgeHours) * time.Hour,
+		MaxEvents: maxEvents,
+	})
+}
+
 // LoadInitialData loads cached data for faster startup
 func (cs *ClusterService) LoadInitialData(clusterID string, group, version, resource string) ([]map[string]any, string, error) {
 	gvr := schema.GroupVersionResource{
@@ -379,5 +666,16 @@ func (cs *ClusterService) LoadInitialData(clusterID string, group, version, reso
 
 // Shutdown gracefully shuts down the service
 func (cs *ClusterService) Shutdown() {
+	cs.subscriptionMu.Lock()
+	for _, shared := range cs.watchers {
+		if shared.idleTimer != nil {
+			shared.idleTimer.Stop()
+		}
+	}
+	cs.subscriptionMu.Unlock()
+
+	cs.tableWatches.stopCluster("")
+	cs.eventsWatches.stopCluster("")
 	cs.informerManager.Shutdown()
+	cs.events.Stop()
 }
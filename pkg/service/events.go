@@ -0,0 +1,59 @@
+package service
+
+import (
+	"ksight/pkg/informer"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// EventsWatchResult are the initial events of an events watch, later changes are
+// emitted as "events:event" events carrying the watch ID
+type EventsWatchResult struct {
+	WatchID string                     `json:"watchId"`
+	Events  []informer.KubernetesEvent `json:"events"`
+}
+
+// EventsWatchEvent is a change of an event of an events watch
+type EventsWatchEvent struct {
+	WatchID string `json:"watchId"`
+	informer.KubernetesEventChange
+}
+
+// GetEventsFor returns the Kubernetes events of an object, oldest first
+func (cs *ClusterService) GetEventsFor(clusterID, group, version, resource, namespace, name string) ([]informer.KubernetesEvent, error) {
+	gvr := schema.GroupVersionResource{
+		Group:    group,
+		Version:  version,
+		Resource: resource,
+	}
+
+	return cs.informerManager.GetEventsFor(clusterID, gvr, namespace, name)
+}
+
+// WatchEventsFor returns the Kubernetes events of an object and emits their changes
+// until StopEventsWatch is called
+func (cs *ClusterService) WatchEventsFor(clusterID, group, version, resource, namespace, name string) (EventsWatchResult, error) {
+	gvr := schema.GroupVersionResource{
+		Group:    group,
+		Version:  version,
+		Resource: resource,
+	}
+
+	watchID := cs.eventsWatches.newID()
+
+	events, watch, err := cs.informerManager.WatchEventsFor(clusterID, gvr, namespace, name, func(change informer.KubernetesEventChange) {
+		cs.eventEmitter.Emit("events:event", EventsWatchEvent{WatchID: watchID, KubernetesEventChange: change})
+	})
+	if err != nil {
+		return EventsWatchResult{}, err
+	}
+
+	cs.eventsWatches.add(watchID, clusterID, watch)
+
+	return EventsWatchResult{WatchID: watchID, Events: events}, nil
+}
+
+// StopEventsWatch stops an events watch
+func (cs *ClusterService) StopEventsWatch(watchID string) error {
+	return cs.eventsWatches.stop(watchID)
+}
@@ -0,0 +1,352 @@
+package service
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"ksight/pkg/informer"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+// EventEncoding is how updated objects are sent to the frontend
+type EventEncoding string
+
+const (
+	// EventEncodingFull sends the full object and the full old object of updates
+	EventEncodingFull EventEncoding = "full"
+	// EventEncodingPatch sends updates as JSON merge patches (RFC 7386) against the
+	// object the frontend was sent before, with a full snapshot now and then
+	EventEncodingPatch EventEncoding = "patch"
+)
+
+// EventPipelineConfig configures how resource events are batched for the frontend
+type EventPipelineConfig struct {
+	Window       time.Duration // how long events are collected before they are emitted
+	MaxBatchSize int           // events per emitted batch, a full batch is emitted right away
+	MaxInFlight  uint64        // emitted events the frontend may leave unacknowledged
+	AckTimeout   time.Duration // how long to wait for acknowledgements before resuming anyway
+
+	Encoding         EventEncoding
+	SnapshotInterval int // patched updates of an object between full snapshots, 0 for none
+}
+
+// DefaultEventPipelineConfig returns the pipeline configuration used by the app
+func DefaultEventPipelineConfig() EventPipelineConfig {
+	return EventPipelineConfig{
+		Window:       100 * time.Millisecond,
+		MaxBatchSize: 500,
+		MaxInFlight:  5000,
+		AckTimeout:   5 * time.Second,
+
+		Encoding:         EventEncodingFull,
+		SnapshotInterval: 50,
+	}
+}
+
+// SequencedEvent is a resource event with its position in the event stream.
+// With patch encoding, updates carry a merge patch against the object at the base
+// resource version instead of Object, and no event carries OldObject.
+type SequencedEvent struct {
+	Sequence uint64 `json:"sequence"`
+	informer.Event
+
+	Patch               json.RawMessage `json:"patch,omitempty"`
+	BaseResourceVersion string          `json:"baseResourceVersion,omitempty"`
+
+	delta bool // sent as a patch
+}
+
+// EventPipeline batches resource events per watcher and emits
+// them as "resource:events" arrays. Events for the same object inside a window
+// are coalesced into one. While the frontend has too many unacknowledged events
+// in flight, batches are held back and keep coalescing.
+type EventPipeline struct {
+	emitter EventEmitter
+	config  EventPipelineConfig
+
+	pending      map[batchKey]*pendingBatch
+	patched      map[objectRef]int      // patches sent per object since its last full snapshot
+	snapshots    map[objectRef]struct{} // objects sent in full next, the frontend lost them
+	sequence     uint64                 // last sequence emitted
+	acked        uint64                 // last sequence acknowledged by the frontend
+	stalledSince time.Time
+	mu           sync.Mutex
+
+	flushCh  chan struct{}
+	stopCh   chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// batchKey identifies the batch of a watcher, events of overlapping watchers are
+// kept apart
+type batchKey struct {
+	clusterID string
+	watcher   informer.WatcherKey
+}
+
+// objectRef identifies an object of a batch
+type objectRef struct {
+	batchKey
+	objectKey string // namespace/name
+}
+
+// pendingBatch collects the events of a batch until it is emitted
+type pendingBatch struct {
+	events  []informer.Event
+	objects map[string]int // namespace/name -> index in events
+}
+
+// NewEventPipeline creates and starts an event pipeline
+func NewEventPipeline(emitter EventEmitter, config EventPipelineConfig) *EventPipeline {
+	p := &EventPipeline{
+		emitter:   emitter,
+		config:    config,
+		pending:   make(map[batchKey]*pendingBatch),
+		patched:   make(map[objectRef]int),
+		snapshots: make(map[objectRef]struct{}),
+		flushCh:   make(chan struct{}, 1),
+		stopCh:    make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	go p.run()
+	return p
+}
+
+// Push adds an event to the batch of its watcher
+func (p *EventPipeline) Push(event informer.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := batchKey{clusterID: event.ClusterID, watcher: event.Watcher}
+	batch, exists := p.pending[key]
+	if !exists {
+		batch = &pendingBatch{objects: make(map[string]int)}
+		p.pending[key] = batch
+	}
+
+	batch.add(event)
+	if len(batch.events) >= p.config.MaxBatchSize {
+		p.requestFlush()
+	}
+}
+
+// Resync pushes an event carrying the current object for the frontend, it is sent
+// in full so that later patches apply to it
+func (p *EventPipeline) Resync(event informer.Event) {
+	p.mu.Lock()
+	if p.config.Encoding == EventEncodingPatch {
+		key := batchKey{clusterID: event.ClusterID, watcher: event.Watcher}
+		p.snapshots[objectRef{batchKey: key, objectKey: event.Namespace + "/" + event.Name}] = struct{}{}
+	}
+	p.mu.Unlock()
+
+	p.Push(event)
+}
+
+// SetEncoding sets how updated objects are sent from the next batch on
+func (p *EventPipeline) SetEncoding(encoding EventEncoding) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.config.Encoding = encoding
+	p.patched = make(map[objectRef]int)
+	p.snapshots = make(map[objectRef]struct{})
+}
+
+// Ack acknowledges all events up to and including the given sequence
+func (p *EventPipeline) Ack(sequence uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if sequence > p.acked && sequence <= p.sequence {
+		p.acked = sequence
+	}
+	p.stalledSince = time.Time{}
+	p.requestFlush()
+}
+
+// Stop emits the remaining events and stops the pipeline
+func (p *EventPipeline) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stopCh)
+	})
+	<-p.done
+}
+
+// run emits the pending batches every window, or earlier when a batch is full
+func (p *EventPipeline) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.config.Window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopCh:
+			p.flush(true)
+			return
+		case <-ticker.C:
+		case <-p.flushCh:
+		}
+		p.flush(false)
+	}
+}
+
+// requestFlush wakes up the pipeline. The caller must hold the lock.
+func (p *EventPipeline) requestFlush() {
+	select {
+	case p.flushCh <- struct{}{}:
+	default:
+	}
+}
+
+// flush emits all pending batches unless the frontend is behind
+func (p *EventPipeline) flush(force bool) {
+	p.mu.Lock()
+	if len(p.pending) == 0 || (!force && p.behind()) {
+		p.mu.Unlock()
+		return
+	}
+
+	encoding := p.config.Encoding
+	var batches [][]SequencedEvent
+	for key, batch := range p.pending {
+		delete(p.pending, key)
+
+		var current []SequencedEvent
+		for _, event := range batch.events {
+			if event.Type == "" {
+				continue // Coalesced away
+			}
+
+			p.sequence++
+			sequenced := SequencedEvent{Sequence: p.sequence, Event: event}
+			if encoding == EventEncodingPatch {
+				sequenced.delta = p.sendAsPatch(objectRef{batchKey: key, objectKey: event.Namespace + "/" + event.Name}, event)
+			}
+			current = append(current, sequenced)
+			if len(current) >= p.config.MaxBatchSize {
+				batches = append(batches, current)
+				current = nil
+			}
+		}
+		if len(current) > 0 {
+			batches = append(batches, current)
+		}
+	}
+	p.mu.Unlock()
+
+	// Patches are created outside the lock, flushes do not overlap
+	for _, batch := range batches {
+		if encoding == EventEncodingPatch {
+			for i := range batch {
+				batch[i].encodePatch()
+			}
+		}
+		p.emitter.Emit("resource:events", batch)
+	}
+}
+
+// sendAsPatch reports whether an event is sent as a patch against the old object
+// and counts the patches of the object. The caller must hold the lock.
+func (p *EventPipeline) sendAsPatch(ref objectRef, event informer.Event) bool {
+	_, resync := p.snapshots[ref]
+	delete(p.snapshots, ref)
+
+	switch {
+	case event.Type != "MODIFIED" || resync:
+		delete(p.patched, ref)
+		return false
+	case event.OldObject == nil || event.OldObject.GetUID() != event.Object.GetUID():
+		// Recreated objects have nothing in common with the old object
+		delete(p.patched, ref)
+		return false
+	case p.config.SnapshotInterval > 0 && p.patched[ref] >= p.config.SnapshotInterval:
+		delete(p.patched, ref)
+		return false
+	}
+
+	p.patched[ref]++
+	return true
+}
+
+// encodePatch replaces the objects of a patch encoded event by the merge patch
+// between them. Events that cannot be patched are sent in full.
+func (e *SequencedEvent) encodePatch() {
+	oldObject := e.OldObject
+	e.OldObject = nil
+	if !e.delta {
+		return
+	}
+
+	original, err := json.Marshal(oldObject)
+	if err != nil {
+		return
+	}
+	modified, err := json.Marshal(e.Object)
+	if err != nil {
+		return
+	}
+	patch, err := jsonpatch.CreateMergePatch(original, modified)
+	if err != nil {
+		return
+	}
+
+	e.Patch = patch
+	e.BaseResourceVersion = oldObject.GetResourceVersion()
+	e.Object = nil
+}
+
+// behind reports whether too many emitted events are unacknowledged.
+// The caller must hold the lock.
+func (p *EventPipeline) behind() bool {
+	if p.config.MaxInFlight == 0 || p.sequence-p.acked < p.config.MaxInFlight {
+		return false
+	}
+
+	if p.stalledSince.IsZero() {
+		p.stalledSince = time.Now()
+	}
+	if time.Since(p.stalledSince) < p.config.AckTimeout {
+		return true
+	}
+
+	// The frontend stopped acknowledging, resume instead of holding events forever
+	p.acked = p.sequence
+	p.stalledSince = time.Time{}
+	return false
+}
+
+// add coalesces an event with an earlier event for the same object in the batch
+func (b *pendingBatch) add(event informer.Event) {
+	objectKey := event.Namespace + "/" + event.Name
+	index, exists := b.objects[objectKey]
+	if !exists {
+		b.objects[objectKey] = len(b.events)
+		b.events = append(b.events, event)
+		return
+	}
+
+	previous := b.events[index]
+	switch {
+	case previous.Type == "ADDED" && event.Type == "DELETED":
+		// The frontend never saw the object
+		b.events[index].Type = ""
+		delete(b.objects, objectKey)
+		return
+	case previous.Type == "ADDED":
+		event.Type = "ADDED"
+		event.OldObject = nil
+	case previous.Type == "DELETED" && event.Type != "DELETED":
+		// The object was recreated
+		event.Type = "MODIFIED"
+		event.OldObject = previous.Object
+	case previous.Type == "MODIFIED" && event.Type == "MODIFIED":
+		event.OldObject = previous.OldObject
+	}
+	b.events[index] = event
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"ksight/pkg/informer"
+)
+
+// DefaultWatcherGracePeriod is how long a watcher without subscriptions keeps running
+const DefaultWatcherGracePeriod = 30 * time.Second
+
+// watcherRef identifies a watcher of a cluster
+type watcherRef struct {
+	clusterID string
+	key       informer.WatcherKey
+}
+
+// sharedWatcher tracks the subscriptions of a watcher shared between views
+type sharedWatcher struct {
+	request   ResourceWatchRequest
+	refCount  int
+	idleTimer *time.Timer
+	started   chan struct{} // closed once the watcher started or failed to
+	startErr  error
+}
+
+// ResourceWatcherInfo describes a shared watcher and its subscriptions
+type ResourceWatcherInfo struct {
+	ResourceWatchRequest
+	Subscriptions int  `json:"subscriptions"`
+	Idle          bool `json:"idle"`
+}
+
+// WatcherErrorEvent is emitted as "watcher:error" when a watcher fails to list or watch
+type WatcherErrorEvent struct {
+	ResourceWatchRequest
+	FailedNamespace string    `json:"failedNamespace,omitempty"`
+	Type            string    `json:"type"` // unauthorized, forbidden, gone, notfound, network, timeout, unknown
+	Message         string    `json:"message"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+// newWatcherErrorEvent describes a watch error by the request of the failing watcher
+func newWatcherErrorEvent(watchError informer.WatchError) WatcherErrorEvent {
+	return WatcherErrorEvent{
+		ResourceWatchRequest: newResourceWatchRequest(watchError.ClusterID, watchError.Key),
+		FailedNamespace:      watchError.Namespace,
+		Type:                 watchError.Type,
+		Message:              watchError.Message,
+		Timestamp:            watchError.Timestamp,
+	}
+}
+
+// GetResourceWatchers returns the watchers of a cluster started through subscriptions
+func (cs *ClusterService) GetResourceWatchers(clusterID string) []ResourceWatcherInfo {
+	cs.subscriptionMu.Lock()
+	defer cs.subscriptionMu.Unlock()
+
+	var result []ResourceWatcherInfo
+	for ref, shared := range cs.watchers {
+		if ref.clusterID == clusterID {
+			result = append(result, ResourceWatcherInfo{
+				ResourceWatchRequest: shared.request,
+				Subscriptions:        shared.refCount,
+				Idle:                 shared.refCount == 0,
+			})
+		}
+	}
+	return result
+}
+
+// WatcherStatus describes the state of a running watcher and its subscriptions
+type WatcherStatus struct {
+	ResourceWatchRequest
+	Subscriptions       int                  `json:"subscriptions"`
+	HasSynced           bool                 `json:"hasSynced"`
+	ObjectCount         int                  `json:"objectCount"`
+	LastEventTime       time.Time            `json:"lastEventTime"`
+	LastResourceVersion string               `json:"lastResourceVersion"`
+	EventsPerSecond     float64              `json:"eventsPerSecond"`
+	LastError           *informer.WatchError `json:"lastError,omitempty"`
+}
+
+// GetWatcherStatus returns the state of every active watcher of a cluster
+func (cs *ClusterService) GetWatcherStatus(clusterID string) ([]WatcherStatus, error) {
+	statuses, err := cs.informerManager.GetWatcherStatus(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	cs.subscriptionMu.Lock()
+	defer cs.subscriptionMu.Unlock()
+
+	result := make([]WatcherStatus, 0, len(statuses))
+	for _, status := range statuses {
+		var subscriptions int
+		if shared, exists := cs.watchers[watcherRef{clusterID: clusterID, key: status.Key}]; exists {
+			subscriptions = shared.refCount
+		}
+
+		result = append(result, WatcherStatus{
+			ResourceWatchRequest: newResourceWatchRequest(clusterID, status.Key),
+			Subscriptions:        subscriptions,
+			HasSynced:            status.HasSynced,
+			ObjectCount:          status.ObjectCount,
+			LastEventTime:        status.LastEventTime,
+			LastResourceVersion:  status.LastResourceVersion,
+			EventsPerSecond:      status.EventsPerSecond,
+			LastError:            status.LastError,
+		})
+	}
+	return result, nil
+}
+
+// SetWatcherGracePeriod sets how long an idle watcher keeps running before it is
+// stopped, zero stops it as soon as its last subscription is removed
+func (cs *ClusterService) SetWatcherGracePeriod(gracePeriod time.Duration) {
+	cs.subscriptionMu.Lock()
+	defer cs.subscriptionMu.Unlock()
+
+	cs.watcherGracePeriod = gracePeriod
+}
+
+// acquireWatcher adds a reference to a watcher, starting it if needed. Starting
+// checks API access and may wait for a slow cluster, so the subscription lock is
+// released meanwhile; subscriptions of the same watcher wait for it to start.
+// The caller must hold the subscription lock.
+func (cs *ClusterService) acquireWatcher(ref watcherRef, request ResourceWatchRequest) error {
+	if shared, exists := cs.watchers[ref]; exists {
+		shared.refCount++
+		if shared.idleTimer != nil {
+			shared.idleTimer.Stop()
+			shared.idleTimer = nil
+		}
+		return cs.waitStarted(shared)
+	}
+
+	// The slot is reserved before the lock is released
+	shared := &sharedWatcher{
+		request:  request.withWatcherKey(ref.key),
+		refCount: 1,
+		started:  make(chan struct{}),
+	}
+	cs.watchers[ref] = shared
+
+	cs.subscriptionMu.Unlock()
+	err := cs.informerManager.AddResourceWatcher(ref.clusterID, ref.key)
+	cs.subscriptionMu.Lock()
+
+	// The cluster may have been removed while the watcher started
+	if err == nil && cs.watchers[ref] != shared {
+		err = fmt.Errorf("watcher for %s was removed while it started", ref.key.String())
+	}
+	shared.startErr = err
+	close(shared.started)
+
+	if err != nil {
+		if cs.watchers[ref] == shared {
+			delete(cs.watchers, ref)
+		}
+		return err
+	}
+
+	// Emit watcher added event
+	cs.eventEmitter.Emit("watcher:added", shared.request)
+
+	return nil
+}
+
+// waitStarted waits for a watcher started by another subscription and returns
+// the error it failed with. The caller must hold the subscription lock.
+func (cs *ClusterService) waitStarted(shared *sharedWatcher) error {
+	select {
+	case <-shared.started:
+		return shared.startErr
+	default:
+	}
+
+	cs.subscriptionMu.Unlock()
+	<-shared.started
+	cs.subscriptionMu.Lock()
+	return shared.startErr
+}
+
+// releaseWatcher drops a reference to a watcher and schedules the stop of an idle one.
+// The caller must hold the subscription lock.
+func (cs *ClusterService) releaseWatcher(ref watcherRef) {
+	shared, exists := cs.watchers[ref]
+	if !exists {
+		return
+	}
+
+	shared.refCount--
+	if shared.refCount > 0 {
+		return
+	}
+
+	if cs.watcherGracePeriod <= 0 {
+		cs.stopWatcher(ref)
+		return
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(cs.watcherGracePeriod, func() {
+		cs.subscriptionMu.Lock()
+		defer cs.subscriptionMu.Unlock()
+
+		// The watcher may have been resubscribed or rescheduled meanwhile
+		if current, exists := cs.watchers[ref]; exists && current.idleTimer == timer {
+			cs.stopWatcher(ref)
+		}
+	})
+	shared.idleTimer = timer
+}
+
+// stopWatcher stops a watcher regardless of its references. The watcher is removed
+// right away, its informers are waited for without blocking other subscriptions.
+// The caller must hold the subscription lock.
+func (cs *ClusterService) stopWatcher(ref watcherRef) {
+	shared := cs.watchers[ref]
+	delete(cs.watchers, ref)
+
+	stop, err := cs.informerManager.DetachResourceWatcher(ref.clusterID, ref.key)
+	if err != nil {
+		fmt.Printf("Warning: failed to stop idle watcher %s: %v\n", ref.key.String(), err)
+		return
+	}
+	go stop()
+
+	// Emit watcher removed event
+	cs.eventEmitter.Emit("watcher:removed", shared.request)
+}
+
+// rescopeSubscription moves a subscription to the watcher of a new key. A watcher
+// used by this subscription only is rescoped in place, so only the changed
+// namespace is listed. The caller must hold the subscription lock, it is released
+// while a new watcher starts.
+func (cs *ClusterService) rescopeSubscription(subscriptionID string, newKey informer.WatcherKey, rescope func(clusterID string, key informer.WatcherKey) (informer.WatcherKey, error)) (ResourceWatchRequest, error) {
+	ref := cs.subscriptions[subscriptionID]
+	shared := cs.watchers[ref]
+
+	newRef := watcherRef{clusterID: ref.clusterID, key: newKey}
+	if newRef == ref {
+		return shared.request, nil
+	}
+
+	if _, exists := cs.watchers[newRef]; !exists && shared.refCount == 1 {
+		key, err := rescope(ref.clusterID, ref.key)
+		if err != nil {
+			return shared.request, err
+		}
+
+		newRef.key = key
+		shared.request = shared.request.withWatcherKey(key)
+		delete(cs.watchers, ref)
+		cs.watchers[newRef] = shared
+		cs.subscriptions[subscriptionID] = newRef
+
+		// Emit watcher updated event
+		cs.eventEmitter.Emit("watcher:updated", shared.request)
+
+		return shared.request, nil
+	}
+
+	// The watcher is shared, keep it for the other subscriptions
+	if err := cs.acquireWatcher(newRef, shared.request); err != nil {
+		return shared.request, err
+	}
+	if cs.subscriptions[subscriptionID] != ref {
+		// Removed or rescoped while the new watcher started
+		cs.releaseWatcher(newRef)
+		return shared.request, fmt.Errorf("subscription %s changed while its watcher started", subscriptionID)
+	}
+	cs.subscriptions[subscriptionID] = newRef
+	cs.releaseWatcher(ref)
+
+	return cs.watchers[newRef].request, nil
+}
+
+// dropClusterSubscriptions forgets the subscriptions and watchers of a removed cluster
+func (cs *ClusterService) dropClusterSubscriptions(clusterID string) {
+	cs.subscriptionMu.Lock()
+	defer cs.subscriptionMu.Unlock()
+
+	for ref, shared := range cs.watchers {
+		if ref.clusterID != clusterID {
+			continue
+		}
+		if shared.idleTimer != nil {
+			shared.idleTimer.Stop()
+		}
+		delete(cs.watchers, ref)
+	}
+
+	for id, ref := range cs.subscriptions {
+		if ref.clusterID == clusterID {
+			delete(cs.subscriptions, id)
+		}
+	}
+}
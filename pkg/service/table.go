@@ -0,0 +1,60 @@
+package service
+
+import (
+	"ksight/pkg/informer"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// TableWatchResult is the initial table of a table watch, later changes are
+// emitted as "table:event" events carrying the watch ID
+type TableWatchResult struct {
+	WatchID string                  `json:"watchId"`
+	Table   *informer.ResourceTable `json:"table"`
+}
+
+// TableWatchEvent is a row change of a table watch
+type TableWatchEvent struct {
+	WatchID string `json:"watchId"`
+	informer.TableEvent
+}
+
+// ListResourceTable lists resources with the columns rendered by the apiserver,
+// including the additionalPrinterColumns of custom resources
+func (cs *ClusterService) ListResourceTable(clusterID, group, version, resource, namespace, selector string) (*informer.ResourceTable, error) {
+	gvr := schema.GroupVersionResource{
+		Group:    group,
+		Version:  version,
+		Resource: resource,
+	}
+
+	return cs.informerManager.ListResourceTable(clusterID, gvr, namespace, selector)
+}
+
+// WatchResourceTable lists resources as a table and keeps the rows up to date
+// until StopResourceTableWatch is called
+func (cs *ClusterService) WatchResourceTable(clusterID, group, version, resource, namespace, selector string) (TableWatchResult, error) {
+	gvr := schema.GroupVersionResource{
+		Group:    group,
+		Version:  version,
+		Resource: resource,
+	}
+
+	watchID := cs.tableWatches.newID()
+
+	table, watch, err := cs.informerManager.WatchResourceTable(clusterID, gvr, namespace, selector, func(event informer.TableEvent) {
+		cs.eventEmitter.Emit("table:event", TableWatchEvent{WatchID: watchID, TableEvent: event})
+	})
+	if err != nil {
+		return TableWatchResult{}, err
+	}
+
+	cs.tableWatches.add(watchID, clusterID, watch)
+
+	return TableWatchResult{WatchID: watchID, Table: table}, nil
+}
+
+// StopResourceTableWatch stops a table watch
+func (cs *ClusterService) StopResourceTableWatch(watchID string) error {
+	return cs.tableWatches.stop(watchID)
+}
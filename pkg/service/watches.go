@@ -0,0 +1,77 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+)
+
+// watchRegistry holds the running watches of one kind by watch ID, so the
+// frontend can stop them and they are stopped with their cluster
+type watchRegistry[W interface{ Stop() }] struct {
+	name    string
+	watches map[string]registeredWatch[W]
+	nextID  int
+	mu      sync.Mutex
+}
+
+// registeredWatch is a running watch of a cluster
+type registeredWatch[W interface{ Stop() }] struct {
+	clusterID string
+	watch     W
+}
+
+// newWatchRegistry creates a registry for watches named like "<name>_<n>"
+func newWatchRegistry[W interface{ Stop() }](name string) *watchRegistry[W] {
+	return &watchRegistry[W]{
+		name:    name,
+		watches: make(map[string]registeredWatch[W]),
+	}
+}
+
+// newID returns the ID of a new watch, handlers of the watch need it before it starts
+func (r *watchRegistry[W]) newID() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	return fmt.Sprintf("%s_%d", r.name, r.nextID)
+}
+
+// add registers a started watch of a cluster
+func (r *watchRegistry[W]) add(watchID, clusterID string, watch W) {
+	r.mu.Lock()
+	r.watches[watchID] = registeredWatch[W]{clusterID: clusterID, watch: watch}
+	r.mu.Unlock()
+}
+
+// stop stops a watch by ID
+func (r *watchRegistry[W]) stop(watchID string) error {
+	r.mu.Lock()
+	watch, exists := r.watches[watchID]
+	delete(r.watches, watchID)
+	r.mu.Unlock()
+
+	if !exists {
+		return fmt.Errorf("%s watch %s not found", r.name, watchID)
+	}
+
+	watch.watch.Stop()
+	return nil
+}
+
+// stopCluster stops the watches of a cluster, or all of them for an empty cluster ID
+func (r *watchRegistry[W]) stopCluster(clusterID string) {
+	r.mu.Lock()
+	var stopped []W
+	for watchID, watch := range r.watches {
+		if clusterID == "" || watch.clusterID == clusterID {
+			stopped = append(stopped, watch.watch)
+			delete(r.watches, watchID)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, watch := range stopped {
+		watch.Stop()
+	}
+}
@@ -2,13 +2,21 @@ package test
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
 
 	"ksight/pkg/informer"
 )
@@ -76,6 +84,102 @@ var _ = Describe("InformerManager", func() {
 			Expect(clusters).NotTo(HaveKey(testClusterID))
 		})
 
+		It("should report an unreachable cluster as disconnected", func() {
+			content := strings.Replace(getKubeconfigContent(), cfg.Host, "https://127.0.0.1:1", 1)
+			kubeconfigPath := filepath.Join(tempDir, "unreachable-kubeconfig.yaml")
+			Expect(os.WriteFile(kubeconfigPath, []byte(content), 0600)).To(Succeed())
+
+			var statusChanges []string
+			var statusMutex sync.Mutex
+			testInformerManager.SetHealthCheckInterval(200 * time.Millisecond)
+			testInformerManager.SetStatusHandler(func(clusterID string) {
+				statusMutex.Lock()
+				statusChanges = append(statusChanges, clusterID)
+				statusMutex.Unlock()
+			})
+
+			err := testInformerManager.AddCluster(testClusterID, "test-cluster", kubeconfigPath, "test-context")
+			Expect(err).NotTo(HaveOccurred())
+
+			clusters := testInformerManager.GetClusters()
+			Expect(clusters).To(HaveKey(testClusterID))
+			Expect(clusters[testClusterID].Status).To(Equal("disconnected"))
+			Expect(clusters[testClusterID].LastError).NotTo(BeEmpty())
+
+			// The monitor keeps probing without a transition while the cluster stays down
+			Consistently(func() []string {
+				statusMutex.Lock()
+				defer statusMutex.Unlock()
+				return statusChanges
+			}, 2*time.Second, 200*time.Millisecond).Should(BeEmpty())
+		})
+
+		It("should restart watchers once the cluster recovers from an outage", func() {
+			proxy := startTestProxy()
+			defer proxy.Stop()
+
+			testNS := createTestNamespace("test-reconnect")
+			Expect(k8sClient.Create(ctx, testNS)).To(Succeed())
+			defer deleteResource(testNS)
+
+			testInformerManager.SetHealthCheckInterval(200 * time.Millisecond)
+			Expect(testInformerManager.AddCluster(testClusterID, "test-cluster", proxy.kubeconfig(), "test-context")).To(Succeed())
+
+			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+			key := informer.NewWatcherKey(podGVR, "test-reconnect")
+			Expect(testInformerManager.AddResourceWatcher(testClusterID, key)).To(Succeed())
+
+			watcher := testInformerManager.GetClusters()[testClusterID].Informers[key]
+			Eventually(watcher.HasSynced, 10*time.Second).Should(BeTrue())
+
+			existingPod := createTestPod("test-reconnect", "existing-pod")
+			Expect(k8sClient.Create(ctx, existingPod)).To(Succeed())
+			defer deleteResource(existingPod)
+			addedEvents := func(name string) func() int {
+				return func() int {
+					eventMutex.RLock()
+					defer eventMutex.RUnlock()
+					count := 0
+					for _, event := range receivedEvents {
+						if event.Type == "ADDED" && event.Name == name {
+							count++
+						}
+					}
+					return count
+				}
+			}
+			Eventually(addedEvents("existing-pod"), 10*time.Second).Should(Equal(1))
+			interrupted := watcher.Informers()["test-reconnect"]
+
+			status := func() string {
+				status, _ := testInformerManager.GetClusters()[testClusterID].Health()
+				return status
+			}
+
+			proxy.Stop()
+			Eventually(status, 10*time.Second).Should(Equal("disconnected"))
+			// The informer synced before the outage, it is restarted all the same
+			Expect(interrupted.HasSynced()).To(BeTrue())
+
+			proxy.Start()
+			Eventually(status, 10*time.Second).Should(Equal("connected"))
+			Eventually(func() bool {
+				return watcher.Informers()["test-reconnect"] != interrupted
+			}, 10*time.Second).Should(BeTrue())
+			Eventually(watcher.HasSynced, 10*time.Second).Should(BeTrue())
+
+			// The restarted informer resumes from the objects it held without replaying them
+			Expect(watcher.List()).To(HaveLen(1))
+
+			testPod := createTestPod("test-reconnect", "reconnect-pod")
+			Expect(k8sClient.Create(ctx, testPod)).To(Succeed())
+			defer deleteResource(testPod)
+
+			Eventually(watcher.List, 10*time.Second).Should(HaveLen(2))
+			Eventually(addedEvents("reconnect-pod"), 10*time.Second).Should(Equal(1))
+			Expect(addedEvents("existing-pod")()).To(Equal(1))
+		})
+
 		It("should return error when removing non-existent cluster", func() {
 			err := testInformerManager.RemoveCluster("non-existent")
 			Expect(err).To(HaveOccurred())
@@ -83,6 +187,149 @@ var _ = Describe("InformerManager", func() {
 		})
 	})
 
+	Context("Resource Types", func() {
+		BeforeEach(func() {
+			kubeconfigPath := writeKubeconfigToTempFile()
+			err := testInformerManager.AddCluster(testClusterID, "test-cluster", kubeconfigPath, "test-context")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should describe resource types and cache them on disk", func() {
+			apiResources, err := testInformerManager.GetAPIResources(testClusterID)
+			Expect(err).NotTo(HaveOccurred())
+			resourceTypes := apiResources.Resources
+
+			Expect(resourceTypes).To(ContainElement(And(
+				HaveField("Resource", "pods"),
+				HaveField("Kind", "Pod"),
+				HaveField("Namespaced", BeTrue()),
+				HaveField("Verbs", ContainElements("list", "watch", "delete")),
+				HaveField("ShortNames", ContainElement("po")),
+				HaveField("Categories", ContainElement("all")),
+				HaveField("Subresources", ContainElements("status", "log")),
+			)))
+			Expect(resourceTypes).To(ContainElement(And(
+				HaveField("Resource", "nodes"),
+				HaveField("Namespaced", BeFalse()),
+			)))
+			Expect(resourceTypes).To(ContainElement(And(
+				HaveField("Group", "autoscaling"),
+				HaveField("Resource", "horizontalpodautoscalers"),
+				HaveField("Version", "v2"),
+				HaveField("Versions", ContainElements("v2", "v1")),
+			)))
+			Expect(resourceTypes).NotTo(ContainElement(HaveField("Resource", ContainSubstring("/"))))
+
+			// Discovery documents are kept on disk for the next start
+			cacheFiles, err := filepath.Glob(filepath.Join(tempDir, "cache", "discovery", "*", "servergroups.json"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cacheFiles).NotTo(BeEmpty())
+		})
+
+		It("should return the resolved groups while an APIService is unavailable", func() {
+			apiService := &unstructured.Unstructured{
+				Object: map[string]any{
+					"apiVersion": "apiregistration.k8s.io/v1",
+					"kind":       "APIService",
+					"metadata": map[string]any{
+						"name": "v1beta1.broken.ksight.test",
+					},
+					"spec": map[string]any{
+						"group":                 "broken.ksight.test",
+						"version":               "v1beta1",
+						"groupPriorityMinimum":  int64(100),
+						"versionPriority":       int64(100),
+						"insecureSkipTLSVerify": true,
+						"service": map[string]any{
+							"name":      "missing-service",
+							"namespace": "default",
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, apiService)).To(Succeed())
+			defer deleteResource(apiService)
+
+			partialDiscovery := func(g Gomega) {
+				apiResources, err := testInformerManager.GetAPIResources(testClusterID)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(apiResources.Resources).To(ContainElement(HaveField("Resource", "pods")))
+				g.Expect(apiResources.Resources).NotTo(ContainElement(HaveField("Group", "broken.ksight.test")))
+				g.Expect(apiResources.FailedGroups).To(ContainElement(HaveField("Group", "broken.ksight.test")))
+				for _, failure := range apiResources.FailedGroups {
+					g.Expect(failure.Error).NotTo(BeEmpty())
+				}
+			}
+			// The discovery refresh follows the APIService change
+			Eventually(partialDiscovery, 30*time.Second, time.Second).Should(Succeed())
+			Consistently(partialDiscovery, 3*time.Second, time.Second).Should(Succeed())
+		})
+
+		It("should resolve resource names, short names and kinds", func() {
+			deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+			for _, name := range []string{"deployments", "deploy", "Deployment", "deployments.apps", "Deployment.v1.apps"} {
+				resolved, err := testInformerManager.ResolveResource(testClusterID, name)
+				Expect(err).NotTo(HaveOccurred(), name)
+				Expect(resolved.GVR).To(Equal(deployments), name)
+				Expect(resolved.GVK).To(Equal(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}), name)
+				Expect(resolved.Namespaced).To(BeTrue(), name)
+			}
+
+			resolved, err := testInformerManager.ResolveResource(testClusterID, "po")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.GVR).To(Equal(schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}))
+
+			resolved, err = testInformerManager.ResolveResource(testClusterID, "no")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.GVR.Resource).To(Equal("nodes"))
+			Expect(resolved.Namespaced).To(BeFalse())
+
+			// Served in several versions, the preferred one wins
+			resolved, err = testInformerManager.ResolveResource(testClusterID, "hpa")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.GVR).To(Equal(schema.GroupVersionResource{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"}))
+
+			_, err = testInformerManager.ResolveResource(testClusterID, "nonexistent")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should discover new CRDs and notify about the change", func() {
+			var changedClusters []string
+			var changeMutex sync.Mutex
+			testInformerManager.SetResourceTypesHandler(func(clusterID string) {
+				changeMutex.Lock()
+				changedClusters = append(changedClusters, clusterID)
+				changeMutex.Unlock()
+			})
+
+			gadgetGVR := schema.GroupVersionResource{Group: "discovery.ksight.test", Version: "v1", Resource: "gadgets"}
+			gvrs, err := testInformerManager.GetResourceTypes(testClusterID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gvrs).To(ContainElement(schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}))
+			Expect(gvrs).NotTo(ContainElement(gadgetGVR))
+
+			crd := createTestCRD("discovery.ksight.test", "Gadget", "gadgets")
+			Expect(k8sClient.Create(ctx, crd)).To(Succeed())
+			defer deleteResource(crd)
+
+			Eventually(func() []string {
+				changeMutex.Lock()
+				defer changeMutex.Unlock()
+				return append([]string(nil), changedClusters...)
+			}, 30*time.Second).Should(ContainElement(testClusterID))
+
+			Eventually(func() []schema.GroupVersionResource {
+				gvrs, err := testInformerManager.GetResourceTypes(testClusterID)
+				Expect(err).NotTo(HaveOccurred())
+				return gvrs
+			}, 30*time.Second).Should(ContainElement(gadgetGVR))
+
+			resolved, err := testInformerManager.ResolveResource(testClusterID, "Gadget")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.GVR).To(Equal(gadgetGVR))
+		})
+	})
+
 	Context("Resource Watchers", func() {
 		BeforeEach(func() {
 			kubeconfigPath := writeKubeconfigToTempFile()
@@ -103,7 +350,7 @@ var _ = Describe("InformerManager", func() {
 				Resource: "pods",
 			}
 			
-			err := testInformerManager.AddResourceWatcher(testClusterID, podGVR, "")
+			err := testInformerManager.AddResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR))
 			Expect(err).NotTo(HaveOccurred())
 
 			// Wait for informer to sync
@@ -146,7 +393,7 @@ var _ = Describe("InformerManager", func() {
 				Resource: "deployments",
 			}
 			
-			err := testInformerManager.AddResourceWatcher(testClusterID, deploymentGVR, "")
+			err := testInformerManager.AddResourceWatcher(testClusterID, informer.NewWatcherKey(deploymentGVR))
 			Expect(err).NotTo(HaveOccurred())
 
 			// Wait for informer to sync
@@ -177,21 +424,1164 @@ var _ = Describe("InformerManager", func() {
 				Resource: "pods",
 			}
 			
-			err := testInformerManager.AddResourceWatcher(testClusterID, podGVR, "")
+			err := testInformerManager.AddResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR))
+			Expect(err).NotTo(HaveOccurred())
+
+			err = testInformerManager.RemoveResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should stop delivering events after the watcher is removed", func() {
+			testNS := createTestNamespace("test-removed-watcher")
+			Expect(k8sClient.Create(ctx, testNS)).To(Succeed())
+			defer deleteResource(testNS)
+
+			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+			err := testInformerManager.AddResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR))
+			Expect(err).NotTo(HaveOccurred())
+
+			watcher := testInformerManager.GetClusters()[testClusterID].Informers[informer.NewWatcherKey(podGVR)]
+			Eventually(watcher.HasSynced, 10*time.Second).Should(BeTrue())
+
+			err = testInformerManager.RemoveResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(watcher.Stopped()).To(BeTrue())
+
+			testPod := createTestPod("test-removed-watcher", "unwatched-pod")
+			Expect(k8sClient.Create(ctx, testPod)).To(Succeed())
+			defer deleteResource(testPod)
+
+			Consistently(func() bool {
+				eventMutex.RLock()
+				defer eventMutex.RUnlock()
+				for _, event := range receivedEvents {
+					if event.Name == "unwatched-pod" {
+						return true
+					}
+				}
+				return false
+			}, 3*time.Second).Should(BeFalse())
+
+			// Re-adding the same GVR starts a fresh informer
+			err = testInformerManager.AddResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR))
+			Expect(err).NotTo(HaveOccurred())
+
+			readded := testInformerManager.GetClusters()[testClusterID].Informers[informer.NewWatcherKey(podGVR)]
+			Expect(readded).NotTo(BeIdenticalTo(watcher))
+			Expect(readded.Stopped()).To(BeFalse())
+		})
+
+		It("should only watch the namespaces of a scoped watcher", func() {
+			for _, name := range []string{"test-scoped-a", "test-scoped-b", "test-scoped-c"} {
+				testNS := createTestNamespace(name)
+				Expect(k8sClient.Create(ctx, testNS)).To(Succeed())
+				defer deleteResource(testNS)
+			}
+
+			hasEvent := func(name string) func() bool {
+				return func() bool {
+					eventMutex.RLock()
+					defer eventMutex.RUnlock()
+					for _, event := range receivedEvents {
+						if event.Type == "ADDED" && event.Name == name {
+							return true
+						}
+					}
+					return false
+				}
+			}
+
+			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+			key := informer.NewWatcherKey(podGVR, "test-scoped-b", "test-scoped-a")
+			Expect(key.NamespaceList()).To(Equal([]string{"test-scoped-a", "test-scoped-b"}))
+
+			err := testInformerManager.AddResourceWatcher(testClusterID, key)
+			Expect(err).NotTo(HaveOccurred())
+
+			watcher := testInformerManager.GetClusters()[testClusterID].Informers[key]
+			Expect(watcher.Informers()).To(HaveLen(2))
+			Eventually(watcher.HasSynced, 10*time.Second).Should(BeTrue())
+
+			podA := createTestPod("test-scoped-a", "scoped-pod-a")
+			Expect(k8sClient.Create(ctx, podA)).To(Succeed())
+			defer deleteResource(podA)
+
+			podC := createTestPod("test-scoped-c", "scoped-pod-c")
+			Expect(k8sClient.Create(ctx, podC)).To(Succeed())
+			defer deleteResource(podC)
+
+			Eventually(hasEvent("scoped-pod-a"), 10*time.Second).Should(BeTrue())
+			Consistently(hasEvent("scoped-pod-c"), 2*time.Second).Should(BeFalse())
+
+			// Adding a namespace at runtime lists only that namespace
+			key, err = testInformerManager.AddWatcherNamespace(testClusterID, key, "test-scoped-c")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key.Namespaces).To(Equal("test-scoped-a,test-scoped-b,test-scoped-c"))
+			Eventually(hasEvent("scoped-pod-c"), 10*time.Second).Should(BeTrue())
+
+			key, err = testInformerManager.RemoveWatcherNamespace(testClusterID, key, "test-scoped-a")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(watcher.Informers()).NotTo(HaveKey("test-scoped-a"))
+			Expect(testInformerManager.GetClusters()[testClusterID].Informers).To(HaveKey(key))
+
+			// Removing the last namespace would turn the watcher cluster-wide
+			key, err = testInformerManager.RemoveWatcherNamespace(testClusterID, key, "test-scoped-b")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = testInformerManager.RemoveWatcherNamespace(testClusterID, key, "test-scoped-c")
+			Expect(err).To(HaveOccurred())
+
+			// A resource type the watcher cannot access fails the watcher, not the cluster
+			missingGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "missingthings"}
+			Expect(testInformerManager.AddResourceWatcher(testClusterID, informer.NewWatcherKey(missingGVR, "test-scoped-a"))).NotTo(Succeed())
+			Expect(testInformerManager.GetClusters()[testClusterID].Status).NotTo(Equal("error"))
+		})
+
+		It("should treat watchers with different selectors as distinct", func() {
+			testNS := createTestNamespace("test-selectors")
+			Expect(k8sClient.Create(ctx, testNS)).To(Succeed())
+			defer deleteResource(testNS)
+
+			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+			frontendKey, err := informer.NewWatcherKey(podGVR, "test-selectors").WithSelectors("tier=frontend", "")
+			Expect(err).NotTo(HaveOccurred())
+			namedKey, err := informer.NewWatcherKey(podGVR, "test-selectors").WithSelectors("", "metadata.name=selected-pod-b")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = informer.NewWatcherKey(podGVR).WithSelectors("tier in (", "")
+			Expect(err).To(HaveOccurred())
+
+			Expect(testInformerManager.AddResourceWatcher(testClusterID, frontendKey)).To(Succeed())
+			Expect(testInformerManager.AddResourceWatcher(testClusterID, namedKey)).To(Succeed())
+			Expect(testInformerManager.GetClusters()[testClusterID].Informers).To(HaveLen(2))
+
+			podA := createTestPod("test-selectors", "selected-pod-a")
+			podA.Labels["tier"] = "frontend"
+			Expect(k8sClient.Create(ctx, podA)).To(Succeed())
+			defer deleteResource(podA)
+
+			podB := createTestPod("test-selectors", "selected-pod-b")
+			Expect(k8sClient.Create(ctx, podB)).To(Succeed())
+			defer deleteResource(podB)
+
+			names := func(key informer.WatcherKey) func() []string {
+				return func() []string {
+					var result []string
+					for _, obj := range testInformerManager.GetClusters()[testClusterID].Informers[key].List() {
+						result = append(result, obj.(*unstructured.Unstructured).GetName())
+					}
+					return result
+				}
+			}
+
+			Eventually(names(frontendKey), 10*time.Second).Should(ConsistOf("selected-pod-a"))
+			Eventually(names(namedKey), 10*time.Second).Should(ConsistOf("selected-pod-b"))
+
+			// An unfiltered watcher keeps the cache, objects leaving a selector stay in it
+			allKey := informer.NewWatcherKey(podGVR, "test-selectors")
+			Expect(testInformerManager.AddResourceWatcher(testClusterID, allKey)).To(Succeed())
+			cachedNames := func() []string {
+				resources, _, err := testInformerManager.LoadInitialData(testClusterID, podGVR)
+				Expect(err).NotTo(HaveOccurred())
+				var result []string
+				for _, resource := range resources {
+					result = append(result, resource.GetName())
+				}
+				return result
+			}
+			Eventually(cachedNames, 10*time.Second).Should(ContainElements("selected-pod-a", "selected-pod-b"))
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(podA), podA)).To(Succeed())
+			podA.Labels["tier"] = "backend"
+			Expect(k8sClient.Update(ctx, podA)).To(Succeed())
+			Eventually(names(frontendKey), 10*time.Second).Should(BeEmpty())
+
+			Eventually(func() []informer.WatcherKey {
+				eventMutex.RLock()
+				defer eventMutex.RUnlock()
+				var watchers []informer.WatcherKey
+				for _, event := range receivedEvents {
+					if event.Name == "selected-pod-a" && event.Type == "DELETED" {
+						watchers = append(watchers, event.Watcher)
+					}
+				}
+				return watchers
+			}, 10*time.Second).Should(Equal([]informer.WatcherKey{frontendKey}))
+			Consistently(cachedNames, 2*time.Second).Should(ContainElement("selected-pod-a"))
+		})
+
+		It("should watch metadata only and fetch full objects on demand", func() {
+			testNS := createTestNamespace("test-metadata")
+			Expect(k8sClient.Create(ctx, testNS)).To(Succeed())
+			defer deleteResource(testNS)
+
+			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+			key := informer.NewWatcherKey(podGVR, "test-metadata")
+			key.MetadataOnly = true
+
+			Expect(testInformerManager.AddResourceWatcher(testClusterID, key)).To(Succeed())
+			Expect(testInformerManager.AddResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR, "test-metadata"))).To(Succeed())
+			Expect(testInformerManager.GetClusters()[testClusterID].Informers).To(HaveLen(2))
+
+			testPod := createTestPod("test-metadata", "metadata-pod")
+			Expect(k8sClient.Create(ctx, testPod)).To(Succeed())
+			defer deleteResource(testPod)
+
+			watcher := testInformerManager.GetClusters()[testClusterID].Informers[key]
+			Eventually(watcher.List, 10*time.Second).Should(HaveLen(1))
+
+			obj := watcher.List()[0].(*unstructured.Unstructured)
+			Expect(obj.GetName()).To(Equal("metadata-pod"))
+			Expect(obj.GetLabels()).To(HaveKeyWithValue("app", "test-app"))
+			Expect(obj.Object).NotTo(HaveKey("spec"))
+
+			Eventually(func() bool {
+				eventMutex.RLock()
+				defer eventMutex.RUnlock()
+				for _, event := range receivedEvents {
+					if event.Name == "metadata-pod" && event.MetadataOnly {
+						return true
+					}
+				}
+				return false
+			}, 10*time.Second).Should(BeTrue())
+
+			full, _, err := testInformerManager.FetchResource(testClusterID, podGVR, "test-metadata", "metadata-pod")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(full.Object).To(HaveKey("spec"))
+		})
+
+		It("should list and watch resources rendered as tables", func() {
+			testNS := createTestNamespace("test-table")
+			Expect(k8sClient.Create(ctx, testNS)).To(Succeed())
+			defer deleteResource(testNS)
+
+			firstPod := createTestPod("test-table", "table-pod-1")
+			Expect(k8sClient.Create(ctx, firstPod)).To(Succeed())
+			defer deleteResource(firstPod)
+
+			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+			table, err := testInformerManager.ListResourceTable(testClusterID, podGVR, "test-table", "app=test-app")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(table.Columns).NotTo(BeEmpty())
+			Expect(table.Columns[0].Name).To(Equal("Name"))
+			Expect(table.Rows).To(HaveLen(1))
+			Expect(table.Rows[0].Name).To(Equal("table-pod-1"))
+			Expect(table.Rows[0].Cells).To(HaveLen(len(table.Columns)))
+
+			var tableEvents []informer.TableEvent
+			var tableMutex sync.Mutex
+			_, tableWatch, err := testInformerManager.WatchResourceTable(testClusterID, podGVR, "test-table", "", func(event informer.TableEvent) {
+				tableMutex.Lock()
+				defer tableMutex.Unlock()
+				tableEvents = append(tableEvents, event)
+			})
+			Expect(err).NotTo(HaveOccurred())
+			defer tableWatch.Stop()
+
+			secondPod := createTestPod("test-table", "table-pod-2")
+			Expect(k8sClient.Create(ctx, secondPod)).To(Succeed())
+			defer deleteResource(secondPod)
+
+			Eventually(func() bool {
+				tableMutex.Lock()
+				defer tableMutex.Unlock()
+				for _, event := range tableEvents {
+					if event.Type == "ADDED" && len(event.Rows) == 1 && event.Rows[0].Name == "table-pod-2" {
+						return true
+					}
+				}
+				return false
+			}, 10*time.Second).Should(BeTrue())
+		})
+
+		It("should suppress resync updates of unchanged objects", func() {
+			testNS := createTestNamespace("test-resync")
+			Expect(k8sClient.Create(ctx, testNS)).To(Succeed())
+			defer deleteResource(testNS)
+
+			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+			Expect(testInformerManager.SetResourceResyncPeriod(testClusterID, podGVR, time.Second)).To(Succeed())
+
+			err := testInformerManager.AddResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR, "test-resync"))
+			Expect(err).NotTo(HaveOccurred())
+
+			testPod := createTestPod("test-resync", "resync-pod")
+			Expect(k8sClient.Create(ctx, testPod)).To(Succeed())
+			defer deleteResource(testPod)
+
+			cluster := testInformerManager.GetClusters()[testClusterID]
+			Eventually(cluster.SuppressedEvents.Load, 10*time.Second).Should(BeNumerically(">", 0))
+
+			// Unchanged objects do not rewrite the resource version store
+			storePath := filepath.Join(tempDir, fmt.Sprintf("test_resource_versions_%d.json", GinkgoRandomSeed()))
+			storeModTime := func() time.Time {
+				info, err := os.Stat(storePath)
+				Expect(err).NotTo(HaveOccurred())
+				return info.ModTime()
+			}
+			lastModified := storeModTime()
+			suppressed := cluster.SuppressedEvents.Load()
+			Eventually(cluster.SuppressedEvents.Load, 10*time.Second).Should(BeNumerically(">", suppressed))
+			Expect(storeModTime()).To(Equal(lastModified))
+
+			Consistently(func() bool {
+				eventMutex.RLock()
+				defer eventMutex.RUnlock()
+				for _, event := range receivedEvents {
+					if event.Type == "MODIFIED" && event.Name == "resync-pod" {
+						return true
+					}
+				}
+				return false
+			}, 3*time.Second).Should(BeFalse())
+		})
+
+		It("should report watch errors of a removed resource type", func() {
+			var watchErrors []informer.WatchError
+			var errorMutex sync.Mutex
+			testInformerManager.SetWatchErrorHandler(func(watchError informer.WatchError) {
+				errorMutex.Lock()
+				watchErrors = append(watchErrors, watchError)
+				errorMutex.Unlock()
+			})
+
+			crd := createTestCRD("errors.ksight.test", "Widget", "widgets")
+			Expect(k8sClient.Create(ctx, crd)).To(Succeed())
+			defer deleteResource(crd)
+
+			widgetGVR := schema.GroupVersionResource{Group: "errors.ksight.test", Version: "v1", Resource: "widgets"}
+			waitForServed(widgetGVR, 30*time.Second)
+
+			key := informer.NewWatcherKey(widgetGVR)
+			Expect(testInformerManager.AddResourceWatcher(testClusterID, key)).To(Succeed())
+			watcher := testInformerManager.GetClusters()[testClusterID].Informers[key]
+			Eventually(watcher.HasSynced, 10*time.Second).Should(BeTrue())
+			Expect(watcher.Errors()).To(BeEmpty())
+
+			// Removing the CRD breaks the watch, the relist fails with not found
+			deleteResource(crd)
+
+			Eventually(func() []informer.WatchError {
+				errorMutex.Lock()
+				defer errorMutex.Unlock()
+				return append([]informer.WatchError(nil), watchErrors...)
+			}, 30*time.Second).Should(ContainElement(And(
+				HaveField("ClusterID", testClusterID),
+				HaveField("Key", key),
+				HaveField("Type", "notfound"),
+			)))
+			Expect(watcher.Errors()).NotTo(BeEmpty())
+
+			// Watchers that fail their access check report it as a watch error too
+			missingKey := informer.NewWatcherKey(schema.GroupVersionResource{Group: "", Version: "v1", Resource: "missingthings"})
+			Expect(testInformerManager.AddResourceWatcher(testClusterID, missingKey)).NotTo(Succeed())
+			errorMutex.Lock()
+			Expect(watchErrors).To(ContainElement(And(
+				HaveField("Key", missingKey),
+				HaveField("Type", "notfound"),
+			)))
+			errorMutex.Unlock()
+		})
+
+		It("should report the status of running watchers", func() {
+			testNS := createTestNamespace("test-watcher-status")
+			Expect(k8sClient.Create(ctx, testNS)).To(Succeed())
+			defer deleteResource(testNS)
+
+			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+			key := informer.NewWatcherKey(podGVR, "test-watcher-status")
+			Expect(testInformerManager.AddResourceWatcher(testClusterID, key)).To(Succeed())
+
+			pod := createTestPod("test-watcher-status", "status-pod")
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			defer deleteResource(pod)
+
+			Eventually(func() informer.WatcherStatus {
+				statuses, err := testInformerManager.GetWatcherStatus(testClusterID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(statuses).To(HaveLen(1))
+				return statuses[0]
+			}, 10*time.Second).Should(And(
+				HaveField("Key", key),
+				HaveField("HasSynced", BeTrue()),
+				HaveField("ObjectCount", 1),
+				HaveField("LastEventTime", Not(BeZero())),
+				HaveField("LastResourceVersion", Not(BeEmpty())),
+				HaveField("EventsPerSecond", BeNumerically(">", 0)),
+				HaveField("LastError", BeNil()),
+			))
+
+			_, err := testInformerManager.GetWatcherStatus("non-existent")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should query watched objects with selectors, sorting and pagination", func() {
+			testNS := createTestNamespace("test-query")
+			Expect(k8sClient.Create(ctx, testNS)).To(Succeed())
+			defer deleteResource(testNS)
+
+			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+			query := informer.ResourceQuery{GVR: podGVR, Namespace: "test-query"}
+
+			_, err := testInformerManager.ListResources(testClusterID, query)
+			Expect(err).To(HaveOccurred())
+
+			Expect(testInformerManager.AddResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR))).To(Succeed())
+
+			for i := 1; i <= 5; i++ {
+				pod := createTestPod("test-query", fmt.Sprintf("query-pod-%d", i))
+				pod.Labels["tier"] = []string{"frontend", "backend"}[i%2]
+				Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+				defer deleteResource(pod)
+			}
+
+			names := func(page *informer.ResourcePage) []string {
+				var result []string
+				for _, item := range page.Items {
+					result = append(result, item.GetName())
+				}
+				return result
+			}
+
+			Eventually(func() int {
+				page, err := testInformerManager.ListResources(testClusterID, query)
+				Expect(err).NotTo(HaveOccurred())
+				return page.Total
+			}, 10*time.Second).Should(Equal(5))
+
+			// Pages follow the cursor in descending name order
+			query.SortBy = []string{"-metadata.name"}
+			query.Limit = 2
+			var pages [][]string
+			for {
+				page, err := testInformerManager.ListResources(testClusterID, query)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(page.Total).To(Equal(5))
+				pages = append(pages, names(page))
+				if page.Continue == "" {
+					break
+				}
+				query.Continue = page.Continue
+			}
+			Expect(pages).To(Equal([][]string{
+				{"query-pod-5", "query-pod-4"},
+				{"query-pod-3", "query-pod-2"},
+				{"query-pod-1"},
+			}))
+
+			page, err := testInformerManager.ListResources(testClusterID, informer.ResourceQuery{
+				GVR:           podGVR,
+				Namespace:     "test-query",
+				LabelSelector: "tier=backend",
+				SortBy:        []string{"metadata.name"},
+			})
 			Expect(err).NotTo(HaveOccurred())
+			Expect(names(page)).To(Equal([]string{"query-pod-2", "query-pod-4"}))
 
-			err = testInformerManager.RemoveResourceWatcher(testClusterID, podGVR)
+			page, err = testInformerManager.ListResources(testClusterID, informer.ResourceQuery{
+				GVR:           podGVR,
+				FieldSelector: "metadata.namespace=test-query",
+				NameContains:  "POD-3",
+			})
 			Expect(err).NotTo(HaveOccurred())
+			Expect(names(page)).To(Equal([]string{"query-pod-3"}))
+
+			_, err = testInformerManager.ListResources(testClusterID, informer.ResourceQuery{GVR: podGVR, Continue: "not-a-cursor"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should look up objects by the default and registered indexes", func() {
+			testNS := createTestNamespace("test-indexers")
+			Expect(k8sClient.Create(ctx, testNS)).To(Succeed())
+			defer deleteResource(testNS)
+
+			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+			Expect(testInformerManager.AddResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR, "test-indexers"))).To(Succeed())
+
+			nodePod := createTestPod("test-indexers", "node-pod")
+			nodePod.Spec.NodeName = "worker-1"
+			nodePod.Spec.Containers[0].Image = "busybox:1.36"
+			nodePod.Labels["ksight.test/indexed"] = "yes"
+			Expect(k8sClient.Create(ctx, nodePod)).To(Succeed())
+			defer deleteResource(nodePod)
+
+			otherPod := createTestPod("test-indexers", "other-pod")
+			otherPod.Spec.ServiceAccountName = "builder"
+			Expect(k8sClient.Create(ctx, otherPod)).To(Succeed())
+			defer deleteResource(otherPod)
+
+			lookup := func(indexName, indexValue string) func() []string {
+				return func() []string {
+					page, err := testInformerManager.ListResources(testClusterID, informer.ResourceQuery{
+						GVR:        podGVR,
+						Namespace:  "test-indexers",
+						IndexName:  indexName,
+						IndexValue: indexValue,
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					var names []string
+					for _, item := range page.Items {
+						names = append(names, item.GetName())
+					}
+					return names
+				}
+			}
+
+			Eventually(lookup(informer.IndexNodeName, "worker-1"), 10*time.Second).Should(ConsistOf("node-pod"))
+			Expect(lookup(informer.IndexImage, "busybox:1.36")()).To(ConsistOf("node-pod"))
+			Expect(lookup(informer.IndexImage, "nginx:latest")()).To(ConsistOf("other-pod"))
+			Expect(lookup(informer.IndexLabelKey, "ksight.test/indexed")()).To(ConsistOf("node-pod"))
+			Expect(lookup(informer.IndexLabelKey, "app")()).To(ConsistOf("node-pod", "other-pod"))
+
+			// Registered indexes also cover objects that are already cached
+			Expect(testInformerManager.RegisterIndexer(podGVR, "serviceAccount", "{.spec.serviceAccountName}")).To(Succeed())
+			Expect(lookup("serviceAccount", "builder")()).To(ConsistOf("other-pod"))
+			Expect(testInformerManager.RegisterIndexer(podGVR, "serviceAccount", "{.spec.serviceAccountName}")).NotTo(Succeed())
+			Expect(testInformerManager.RegisterIndexer(podGVR, informer.IndexImage, "{.spec.nodeName}")).NotTo(Succeed())
+			Expect(testInformerManager.RegisterIndexer(podGVR, "broken", "{.spec[")).NotTo(Succeed())
+		})
+
+		It("should build the ownership tree of a resource", func() {
+			testNS := createTestNamespace("test-tree")
+			Expect(k8sClient.Create(ctx, testNS)).To(Succeed())
+			defer deleteResource(testNS)
+
+			deploymentGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+			replicaSetGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}
+			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+			for _, gvr := range []schema.GroupVersionResource{deploymentGVR, replicaSetGVR, podGVR} {
+				Expect(testInformerManager.AddResourceWatcher(testClusterID, informer.NewWatcherKey(gvr, "test-tree"))).To(Succeed())
+			}
+
+			deployment := createTestDeployment("test-tree", "tree-deployment", 3)
+			Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+			defer deleteResource(deployment)
+
+			replicaSet := createTestReplicaSet(deployment, "tree-deployment-abc")
+			Expect(k8sClient.Create(ctx, replicaSet)).To(Succeed())
+			defer deleteResource(replicaSet)
+
+			for i := 1; i <= 3; i++ {
+				pod := createTestPod("test-tree", fmt.Sprintf("tree-pod-%d", i))
+				pod.OwnerReferences = []metav1.OwnerReference{ownerReference(replicaSet, "apps/v1", "ReplicaSet")}
+				Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+				defer deleteResource(pod)
+			}
+
+			var tree *informer.ResourceTreeNode
+			Eventually(func() int {
+				var err error
+				tree, err = testInformerManager.GetResourceTree(testClusterID, podGVR, "test-tree", "tree-pod-1", 2)
+				Expect(err).NotTo(HaveOccurred())
+				if len(tree.Children) == 0 {
+					return 0
+				}
+				return len(tree.Children[0].Children) + tree.Children[0].MoreChildren
+			}, 10*time.Second).Should(Equal(3))
+
+			// The tree starts at the deployment and lists two of the three pods
+			Expect(tree.Kind).To(Equal("Deployment"))
+			Expect(tree.Name).To(Equal("tree-deployment"))
+			Expect(tree.GVR).To(Equal(deploymentGVR))
+			Expect(tree.Status).To(Equal(informer.StatusProgressing))
+			Expect(tree.Message).To(Equal("0/3 ready"))
+			Expect(tree.Children).To(HaveLen(1))
+
+			replicaSetNode := tree.Children[0]
+			Expect(replicaSetNode.Kind).To(Equal("ReplicaSet"))
+			Expect(replicaSetNode.GVR).To(Equal(replicaSetGVR))
+			Expect(replicaSetNode.Children).To(HaveLen(2))
+			Expect(replicaSetNode.MoreChildren).To(Equal(1))
+
+			podNode := replicaSetNode.Children[0]
+			Expect(podNode.Name).To(Equal("tree-pod-1"))
+			Expect(podNode.Focus).To(BeTrue())
+			Expect(podNode.Status).To(Equal(informer.StatusProgressing))
+			Expect(replicaSetNode.Children[1].Focus).To(BeFalse())
+
+			_, err := testInformerManager.GetResourceTree(testClusterID, podGVR, "test-tree", "missing-pod", 0)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should build the ownership tree from metadata-only watchers", func() {
+			testNS := createTestNamespace("test-tree-metadata")
+			Expect(k8sClient.Create(ctx, testNS)).To(Succeed())
+			defer deleteResource(testNS)
+
+			deploymentGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+			replicaSetGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}
+			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+			for _, gvr := range []schema.GroupVersionResource{deploymentGVR, replicaSetGVR, podGVR} {
+				key := informer.NewWatcherKey(gvr, "test-tree-metadata")
+				key.MetadataOnly = true
+				Expect(testInformerManager.AddResourceWatcher(testClusterID, key)).To(Succeed())
+			}
+
+			deployment := createTestDeployment("test-tree-metadata", "tree-deployment", 1)
+			Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+			defer deleteResource(deployment)
+
+			replicaSet := createTestReplicaSet(deployment, "tree-deployment-abc")
+			Expect(k8sClient.Create(ctx, replicaSet)).To(Succeed())
+			defer deleteResource(replicaSet)
+
+			pod := createTestPod("test-tree-metadata", "tree-pod")
+			pod.OwnerReferences = []metav1.OwnerReference{ownerReference(replicaSet, "apps/v1", "ReplicaSet")}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			defer deleteResource(pod)
+
+			var tree *informer.ResourceTreeNode
+			Eventually(func() int {
+				var err error
+				tree, err = testInformerManager.GetResourceTree(testClusterID, podGVR, "test-tree-metadata", "tree-pod", 0)
+				Expect(err).NotTo(HaveOccurred())
+				if len(tree.Children) == 0 {
+					return 0
+				}
+				return len(tree.Children[0].Children)
+			}, 10*time.Second).Should(Equal(1))
+
+			// Kinds come from the resource types, statuses of metadata-only children are unknown
+			Expect(tree.Kind).To(Equal("Deployment"))
+			Expect(tree.Status).To(Equal(informer.StatusProgressing))
+			Expect(tree.Children[0].Kind).To(Equal("ReplicaSet"))
+			Expect(tree.Children[0].Status).To(Equal(informer.StatusUnknown))
+			podNode := tree.Children[0].Children[0]
+			Expect(podNode.Kind).To(Equal("Pod"))
+			Expect(podNode.Focus).To(BeTrue())
+			Expect(podNode.Status).To(Equal(informer.StatusUnknown))
+		})
+
+		It("should resolve root controllers and follow owner changes", func() {
+			testNS := createTestNamespace("test-roots")
+			Expect(k8sClient.Create(ctx, testNS)).To(Succeed())
+			defer deleteResource(testNS)
+
+			var updates []informer.RootControllerResult
+			var updateMutex sync.Mutex
+			testInformerManager.SetRootControllerHandler(func(clusterID string, results []informer.RootControllerResult) {
+				updateMutex.Lock()
+				updates = append(updates, results...)
+				updateMutex.Unlock()
+			})
+			latestRoot := func() string {
+				updateMutex.Lock()
+				defer updateMutex.Unlock()
+				if len(updates) == 0 || updates[len(updates)-1].Root == nil {
+					return ""
+				}
+				root := updates[len(updates)-1].Root
+				return root.Kind + "/" + root.Name
+			}
+
+			// Deployments are not watched, they are fetched from the apiserver
+			replicaSetGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}
+			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+			for _, gvr := range []schema.GroupVersionResource{replicaSetGVR, podGVR} {
+				Expect(testInformerManager.AddResourceWatcher(testClusterID, informer.NewWatcherKey(gvr, "test-roots"))).To(Succeed())
+			}
+
+			deployment := createTestDeployment("test-roots", "root-deployment", 1)
+			Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+			defer deleteResource(deployment)
+
+			replicaSet := createTestReplicaSet(deployment, "root-deployment-abc")
+			Expect(k8sClient.Create(ctx, replicaSet)).To(Succeed())
+			defer deleteResource(replicaSet)
+
+			ownedPod := createTestPod("test-roots", "owned-pod")
+			ownedPod.OwnerReferences = []metav1.OwnerReference{ownerReference(replicaSet, "apps/v1", "ReplicaSet")}
+			Expect(k8sClient.Create(ctx, ownedPod)).To(Succeed())
+			defer deleteResource(ownedPod)
+
+			standalonePod := createTestPod("test-roots", "standalone-pod")
+			Expect(k8sClient.Create(ctx, standalonePod)).To(Succeed())
+			defer deleteResource(standalonePod)
+
+			objects := []informer.ObjectReference{
+				{GVR: podGVR, Namespace: "test-roots", Name: "owned-pod"},
+				{GVR: podGVR, Namespace: "test-roots", Name: "standalone-pod"},
+				{GVR: podGVR, Namespace: "test-roots", Name: "missing-pod"},
+			}
+			results, err := testInformerManager.ResolveRootControllers(testClusterID, objects)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(3))
+			Expect(results[0].Error).To(BeEmpty())
+			Expect(results[0].Root).NotTo(BeNil())
+			Expect(results[0].Root.Kind).To(Equal("Deployment"))
+			Expect(results[0].Root.Name).To(Equal("root-deployment"))
+			Expect(results[0].Root.GVR).To(Equal(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}))
+			Expect(results[1].Root).To(BeNil())
+			Expect(results[1].Error).To(BeEmpty())
+			Expect(results[2].Error).NotTo(BeEmpty())
+
+			// Excluded owner kinds end the resolution below them
+			testInformerManager.SetExcludedOwnerKinds([]string{"Deployment.apps"})
+			Eventually(latestRoot, 10*time.Second).Should(Equal("ReplicaSet/root-deployment-abc"))
+			testInformerManager.SetExcludedOwnerKinds(nil)
+			Eventually(latestRoot, 10*time.Second).Should(Equal("Deployment/root-deployment"))
+
+			// Orphaning the replica set makes it the root of its pod
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(replicaSet), replicaSet); err != nil {
+					return err
+				}
+				replicaSet.OwnerReferences = nil
+				return k8sClient.Update(ctx, replicaSet)
+			}, 10*time.Second).Should(Succeed())
+			Eventually(latestRoot, 10*time.Second).Should(Equal("ReplicaSet/root-deployment-abc"))
+
+			results, err = testInformerManager.ResolveRootControllers(testClusterID, objects[:1])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results[0].Root.Kind).To(Equal("ReplicaSet"))
+
+			// Objects no watcher holds anymore are no longer followed
+			Expect(testInformerManager.RemoveResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR, "test-roots"))).To(Succeed())
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(replicaSet), replicaSet); err != nil {
+					return err
+				}
+				replicaSet.OwnerReferences = []metav1.OwnerReference{ownerReference(deployment, "apps/v1", "Deployment")}
+				return k8sClient.Update(ctx, replicaSet)
+			}, 10*time.Second).Should(Succeed())
+			Consistently(latestRoot, 2*time.Second).Should(Equal("ReplicaSet/root-deployment-abc"))
+		})
+
+		It("should find the services, endpoint slices and ingresses of a pod", func() {
+			testNS := createTestNamespace("test-networking")
+			Expect(k8sClient.Create(ctx, testNS)).To(Succeed())
+			defer deleteResource(testNS)
+
+			for _, gvr := range []schema.GroupVersionResource{
+				{Group: "", Version: "v1", Resource: "pods"},
+				{Group: "", Version: "v1", Resource: "services"},
+				{Group: "discovery.k8s.io", Version: "v1", Resource: "endpointslices"},
+				{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"},
+			} {
+				Expect(testInformerManager.AddResourceWatcher(testClusterID, informer.NewWatcherKey(gvr, "test-networking"))).To(Succeed())
+			}
+
+			pod := createTestPod("test-networking", "web-pod")
+			pod.Labels["tier"] = "web"
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			defer deleteResource(pod)
+			pod.Status.PodIP = "10.1.2.3"
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			newObject := func(apiVersion, kind, name string, fields map[string]any) *unstructured.Unstructured {
+				obj := &unstructured.Unstructured{Object: fields}
+				obj.SetAPIVersion(apiVersion)
+				obj.SetKind(kind)
+				obj.SetNamespace("test-networking")
+				obj.SetName(name)
+				return obj
+			}
+			servicePort := []any{map[string]any{"port": int64(80)}}
+			objects := []*unstructured.Unstructured{
+				newObject("v1", "Service", "web", map[string]any{
+					"spec": map[string]any{"selector": map[string]any{"app": "test-app", "tier": "web"}, "ports": servicePort},
+				}),
+				newObject("v1", "Service", "api", map[string]any{
+					"spec": map[string]any{"selector": map[string]any{"app": "test-app", "tier": "api"}, "ports": servicePort},
+				}),
+				newObject("discovery.k8s.io/v1", "EndpointSlice", "web-abc", map[string]any{
+					"addressType": "IPv4",
+					"endpoints": []any{map[string]any{
+						"addresses": []any{"10.1.2.3"},
+						"targetRef": map[string]any{"kind": "Pod", "namespace": "test-networking", "name": "web-pod", "uid": string(pod.UID)},
+					}},
+				}),
+				// A host network pod on the same node has the same IP
+				newObject("discovery.k8s.io/v1", "EndpointSlice", "agent-abc", map[string]any{
+					"addressType": "IPv4",
+					"endpoints": []any{map[string]any{
+						"addresses": []any{"10.1.2.3"},
+						"targetRef": map[string]any{"kind": "Pod", "namespace": "test-networking", "name": "agent-pod", "uid": "agent-pod-uid"},
+					}},
+				}),
+				newObject("discovery.k8s.io/v1", "EndpointSlice", "api-abc", map[string]any{
+					"addressType": "IPv4",
+					"endpoints":   []any{map[string]any{"addresses": []any{"10.1.2.4"}}},
+				}),
+				newObject("networking.k8s.io/v1", "Ingress", "web", map[string]any{
+					"spec": map[string]any{"rules": []any{map[string]any{"http": map[string]any{"paths": []any{map[string]any{
+						"path":     "/",
+						"pathType": "Prefix",
+						"backend":  map[string]any{"service": map[string]any{"name": "web", "port": map[string]any{"number": int64(80)}}},
+					}}}}}},
+				}),
+				newObject("networking.k8s.io/v1", "Ingress", "api", map[string]any{
+					"spec": map[string]any{"defaultBackend": map[string]any{"service": map[string]any{"name": "api", "port": map[string]any{"number": int64(80)}}}},
+				}),
+			}
+			for _, obj := range objects {
+				Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+				defer deleteResource(obj)
+			}
+
+			names := func(objects []*unstructured.Unstructured) []string {
+				var result []string
+				for _, obj := range objects {
+					result = append(result, obj.GetName())
+				}
+				return result
+			}
+
+			var networking *informer.PodNetworking
+			Eventually(func() []string {
+				var err error
+				networking, err = testInformerManager.GetNetworkingFor(testClusterID, "test-networking", "web-pod")
+				Expect(err).NotTo(HaveOccurred())
+				return append(append(names(networking.Services), names(networking.EndpointSlices)...), names(networking.Ingresses)...)
+			}, 10*time.Second).Should(Equal([]string{"web", "web-abc", "web"}))
+			Consistently(func() []string {
+				networking, err := testInformerManager.GetNetworkingFor(testClusterID, "test-networking", "web-pod")
+				Expect(err).NotTo(HaveOccurred())
+				return names(networking.EndpointSlices)
+			}, 2*time.Second).Should(Equal([]string{"web-abc"}))
+
+			Expect(networking.HTTPRoutes).To(BeEmpty())
+			Expect(networking.Unwatched).To(Equal([]string{"httproutes.gateway.networking.k8s.io"}))
+		})
+
+		It("should find what pods use and who uses a config map", func() {
+			testNS := createTestNamespace("test-dependencies")
+			Expect(k8sClient.Create(ctx, testNS)).To(Succeed())
+			defer deleteResource(testNS)
+
+			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+			deploymentGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+			for _, gvr := range []schema.GroupVersionResource{podGVR, deploymentGVR} {
+				Expect(testInformerManager.AddResourceWatcher(testClusterID, informer.NewWatcherKey(gvr, "test-dependencies"))).To(Succeed())
+			}
+
+			pod := createTestPod("test-dependencies", "config-pod")
+			pod.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "registry"}}
+			pod.Spec.Volumes = []corev1.Volume{
+				{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "settings"},
+				}}},
+				{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}},
+			}
+			pod.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "DB_PASSWORD", ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}, Key: "password"},
+			}}}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			defer deleteResource(pod)
+
+			deployment := createTestDeployment("test-dependencies", "config-deployment", 1)
+			deployment.Spec.Template.Spec.Containers[0].EnvFrom = []corev1.EnvFromSource{{
+				ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "settings"}},
+			}}
+			Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+			defer deleteResource(deployment)
+
+			dependencies, err := testInformerManager.GetDependencies(testClusterID, podGVR, "test-dependencies", "config-pod")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dependencies).To(ConsistOf(
+				informer.Dependency{Kind: informer.DependencyServiceAccount, Name: "default", References: []string{"serviceAccountName"}},
+				informer.Dependency{Kind: informer.DependencySecret, Name: "registry", References: []string{"imagePullSecrets"}},
+				informer.Dependency{Kind: informer.DependencyConfigMap, Name: "settings", References: []string{"volume config"}},
+				informer.Dependency{Kind: informer.DependencyPVC, Name: "data", References: []string{"volume data"}},
+				informer.Dependency{Kind: informer.DependencySecret, Name: "db", References: []string{"env DB_PASSWORD of container test-container"}},
+			))
+
+			dependents := func(kind, name string) func() []string {
+				return func() []string {
+					dependents, err := testInformerManager.GetDependents(testClusterID, kind, "test-dependencies", name)
+					Expect(err).NotTo(HaveOccurred())
+
+					var result []string
+					for _, dependent := range dependents {
+						result = append(result, dependent.Kind+"/"+dependent.Name+": "+strings.Join(dependent.References, ", "))
+					}
+					return result
+				}
+			}
+
+			Eventually(dependents(informer.DependencyConfigMap, "settings"), 10*time.Second).Should(Equal([]string{
+				"Deployment/config-deployment: envFrom of container test-container",
+				"Pod/config-pod: volume config",
+			}))
+			Expect(dependents(informer.DependencySecret, "db")()).To(Equal([]string{"Pod/config-pod: env DB_PASSWORD of container test-container"}))
+			Expect(dependents(informer.DependencyServiceAccount, "default")()).To(HaveLen(2))
+			Expect(dependents(informer.DependencyConfigMap, "unused")()).To(BeEmpty())
+
+			_, err = testInformerManager.GetDependents(testClusterID, "Service", "test-dependencies", "settings")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should look up and stream the events of an object", func() {
+			testNS := createTestNamespace("test-events")
+			Expect(k8sClient.Create(ctx, testNS)).To(Succeed())
+			defer deleteResource(testNS)
+
+			pod := createTestPod("test-events", "event-pod")
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			defer deleteResource(pod)
+			otherPod := createTestPod("test-events", "other-pod")
+			Expect(k8sClient.Create(ctx, otherPod)).To(Succeed())
+			defer deleteResource(otherPod)
+
+			createEvent := func(name string, involved *corev1.Pod, reason string) {
+				now := metav1.Now()
+				_, err := clientset.CoreV1().Events("test-events").Create(ctx, &corev1.Event{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-events"},
+					InvolvedObject: corev1.ObjectReference{
+						APIVersion: "v1",
+						Kind:       "Pod",
+						Namespace:  involved.Namespace,
+						Name:       involved.Name,
+						UID:        involved.UID,
+					},
+					Type:           corev1.EventTypeWarning,
+					Reason:         reason,
+					Message:        reason + " happened",
+					Count:          2,
+					FirstTimestamp: now,
+					LastTimestamp:  now,
+					Source:         corev1.EventSource{Component: "kubelet"},
+				}, metav1.CreateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			createEvent("event-pod.1", pod, "BackOff")
+			createEvent("other-pod.1", otherPod, "Pulled")
+
+			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+			var events []informer.KubernetesEvent
+			Eventually(func() int {
+				var err error
+				events, err = testInformerManager.GetEventsFor(testClusterID, podGVR, "test-events", "event-pod")
+				Expect(err).NotTo(HaveOccurred())
+				return len(events)
+			}, 15*time.Second).Should(Equal(1))
+
+			Expect(events[0].Reason).To(Equal("BackOff"))
+			Expect(events[0].Message).To(Equal("BackOff happened"))
+			Expect(events[0].Type).To(Equal("Warning"))
+			Expect(events[0].Count).To(Equal(int64(2)))
+			Expect(events[0].Source).To(Equal("kubelet"))
+			Expect(events[0].Regarding.Kind).To(Equal("Pod"))
+			Expect(events[0].Regarding.UID).To(Equal(string(pod.UID)))
+
+			var changes []informer.KubernetesEventChange
+			var changeMutex sync.Mutex
+			initial, eventsWatch, err := testInformerManager.WatchEventsFor(testClusterID, podGVR, "test-events", "event-pod", func(change informer.KubernetesEventChange) {
+				changeMutex.Lock()
+				changes = append(changes, change)
+				changeMutex.Unlock()
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(initial).To(HaveLen(1))
+
+			createEvent("other-pod.2", otherPod, "Killing")
+			createEvent("event-pod.2", pod, "Unhealthy")
+			Eventually(func() []string {
+				changeMutex.Lock()
+				defer changeMutex.Unlock()
+				var reasons []string
+				for _, change := range changes {
+					reasons = append(reasons, change.Type+" "+change.Event.Reason)
+				}
+				return reasons
+			}, 10*time.Second).Should(Equal([]string{"ADDED Unhealthy"}))
+
+			eventsWatch.Stop()
+			createEvent("event-pod.3", pod, "Failed")
+			Eventually(func() int {
+				events, err := testInformerManager.GetEventsFor(testClusterID, podGVR, "test-events", "event-pod")
+				Expect(err).NotTo(HaveOccurred())
+				return len(events)
+			}, 10*time.Second).Should(Equal(3))
+			changeMutex.Lock()
+			Expect(changes).To(HaveLen(1))
+			changeMutex.Unlock()
+		})
+
+		It("should look up the events the kubelet reports for a node", func() {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "event-node"}}
+			Expect(k8sClient.Create(ctx, node)).To(Succeed())
+			defer deleteResource(node)
+
+			// The kubelet sets the node name as the UID of the involved object
+			now := metav1.Now()
+			_, err := clientset.CoreV1().Events("default").Create(ctx, &corev1.Event{
+				ObjectMeta: metav1.ObjectMeta{Name: "event-node.1", Namespace: "default"},
+				InvolvedObject: corev1.ObjectReference{
+					Kind: "Node",
+					Name: "event-node",
+					UID:  "event-node",
+				},
+				Type:           corev1.EventTypeNormal,
+				Reason:         "NodeReady",
+				Message:        "Node event-node status is now: NodeReady",
+				FirstTimestamp: now,
+				LastTimestamp:  now,
+				Source:         corev1.EventSource{Component: "kubelet", Host: "event-node"},
+			}, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			defer func() {
+				_ = clientset.CoreV1().Events("default").Delete(ctx, "event-node.1", metav1.DeleteOptions{})
+			}()
+
+			nodeGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "nodes"}
+			Eventually(func() []string {
+				events, err := testInformerManager.GetEventsFor(testClusterID, nodeGVR, "", "event-node")
+				Expect(err).NotTo(HaveOccurred())
+				var reasons []string
+				for _, event := range events {
+					reasons = append(reasons, event.Reason)
+				}
+				return reasons
+			}, 15*time.Second).Should(Equal([]string{"NodeReady"}))
+		})
+
+		It("should look up events in the namespace of an object if listing them cluster-wide is forbidden", func() {
+			testNS := createTestNamespace("test-namespaced-events")
+			Expect(k8sClient.Create(ctx, testNS)).To(Succeed())
+			defer deleteResource(testNS)
+
+			pod := createTestPod("test-namespaced-events", "event-pod")
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			defer deleteResource(pod)
+
+			now := metav1.Now()
+			_, err := clientset.CoreV1().Events("test-namespaced-events").Create(ctx, &corev1.Event{
+				ObjectMeta: metav1.ObjectMeta{Name: "event-pod.1", Namespace: "test-namespaced-events"},
+				InvolvedObject: corev1.ObjectReference{
+					APIVersion: "v1",
+					Kind:       "Pod",
+					Namespace:  pod.Namespace,
+					Name:       pod.Name,
+					UID:        pod.UID,
+				},
+				Type:           corev1.EventTypeNormal,
+				Reason:         "Scheduled",
+				Message:        "Successfully assigned the pod",
+				FirstTimestamp: now,
+				LastTimestamp:  now,
+			}, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			// The user may read pods and events of the namespace only
+			_, err = clientset.RbacV1().Roles("test-namespaced-events").Create(ctx, &rbacv1.Role{
+				ObjectMeta: metav1.ObjectMeta{Name: "viewer", Namespace: "test-namespaced-events"},
+				Rules: []rbacv1.PolicyRule{{
+					APIGroups: []string{"", "events.k8s.io"},
+					Resources: []string{"pods", "events"},
+					Verbs:     []string{"get", "list", "watch"},
+				}},
+			}, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = clientset.RbacV1().RoleBindings("test-namespaced-events").Create(ctx, &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "viewer", Namespace: "test-namespaced-events"},
+				RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "Role", Name: "viewer"},
+				Subjects:   []rbacv1.Subject{{APIGroup: "rbac.authorization.k8s.io", Kind: "User", Name: "namespace-viewer"}},
+			}, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			user, err := testEnv.AddUser(envtest.User{Name: "namespace-viewer"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			kubeconfigPath := filepath.Join(tempDir, "namespace-viewer-kubeconfig.yaml")
+			Expect(os.WriteFile(kubeconfigPath, []byte(kubeconfigContentFor(user.Config())), 0600)).To(Succeed())
+
+			viewerClusterID := "namespace-viewer-cluster"
+			Expect(testInformerManager.AddCluster(viewerClusterID, "namespace-viewer", kubeconfigPath, "test-context")).To(Succeed())
+			defer testInformerManager.RemoveCluster(viewerClusterID)
+
+			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+			Eventually(func() []string {
+				events, err := testInformerManager.GetEventsFor(viewerClusterID, podGVR, "test-namespaced-events", "event-pod")
+				Expect(err).NotTo(HaveOccurred())
+				var reasons []string
+				for _, event := range events {
+					reasons = append(reasons, event.Reason)
+				}
+				return reasons
+			}, 10*time.Second).Should(Equal([]string{"Scheduled"}))
+		})
+
+		It("should keep watched events after the cluster deletes them", func() {
+			testNS := createTestNamespace("test-event-history")
+			Expect(k8sClient.Create(ctx, testNS)).To(Succeed())
+			defer deleteResource(testNS)
+
+			eventGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "events"}
+			Expect(testInformerManager.AddResourceWatcher(testClusterID, informer.NewWatcherKey(eventGVR, "test-event-history"))).To(Succeed())
+
+			newEvent := func(name, reason, eventType string) *corev1.Event {
+				now := metav1.Now()
+				return &corev1.Event{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-event-history"},
+					InvolvedObject: corev1.ObjectReference{
+						APIVersion: "v1",
+						Kind:       "Pod",
+						Namespace:  "test-event-history",
+						Name:       "history-pod",
+						UID:        "history-pod-uid",
+					},
+					Type:           eventType,
+					Reason:         reason,
+					Message:        reason + " happened",
+					Count:          1,
+					FirstTimestamp: now,
+					LastTimestamp:  now,
+				}
+			}
+			events := clientset.CoreV1().Events("test-event-history")
+
+			backOff, err := events.Create(ctx, newEvent("history-pod.1", "BackOff", corev1.EventTypeWarning), metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = events.Create(ctx, newEvent("history-pod.2", "Pulled", corev1.EventTypeNormal), metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			history := func(query informer.EventHistoryQuery) func() []string {
+				return func() []string {
+					query.ClusterID = testClusterID
+					recorded, err := testInformerManager.QueryEventHistory(query)
+					Expect(err).NotTo(HaveOccurred())
+
+					var result []string
+					for _, event := range recorded {
+						result = append(result, fmt.Sprintf("%s x%d", event.Reason, event.Count))
+					}
+					return result
+				}
+			}
+			byPod := informer.EventHistoryQuery{RegardingKind: "Pod", RegardingNamespace: "test-event-history", RegardingName: "history-pod"}
+
+			Eventually(history(byPod), 10*time.Second).Should(ConsistOf("BackOff x1", "Pulled x1"))
+
+			// Count updates replace the recorded event
+			backOff.Count = 5
+			backOff.LastTimestamp = metav1.NewTime(backOff.LastTimestamp.Add(time.Minute))
+			_, err = events.Update(ctx, backOff, metav1.UpdateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(history(byPod), 10*time.Second).Should(Equal([]string{"Pulled x1", "BackOff x5"}))
+
+			Expect(events.DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{})).To(Succeed())
+			Consistently(history(byPod), 2*time.Second).Should(HaveLen(2))
+
+			Expect(history(informer.EventHistoryQuery{RegardingUID: "history-pod-uid", Type: "Warning"})()).To(Equal([]string{"BackOff x5"}))
+			Expect(history(informer.EventHistoryQuery{Reason: "Pulled"})()).To(Equal([]string{"Pulled x1"}))
+			Expect(history(informer.EventHistoryQuery{Since: time.Now().Add(time.Hour)})()).To(BeEmpty())
+			Expect(history(informer.EventHistoryQuery{RegardingUID: "history-pod-uid", Limit: 1})()).To(Equal([]string{"BackOff x5"}))
+
+			// Retention keeps the newest events
+			defer testInformerManager.SetEventRetention(informer.DefaultEventRetention)
+			Expect(testInformerManager.SetEventRetention(informer.EventRetention{MaxAge: 24 * time.Hour, MaxEvents: 1})).To(Succeed())
+			Expect(history(byPod)()).To(Equal([]string{"BackOff x5"}))
 		})
 
 		It("should handle multiple watchers for same cluster", func() {
 			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
 			serviceGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "services"}
 			
-			err := testInformerManager.AddResourceWatcher(testClusterID, podGVR, "")
+			err := testInformerManager.AddResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR))
 			Expect(err).NotTo(HaveOccurred())
 			
-			err = testInformerManager.AddResourceWatcher(testClusterID, serviceGVR, "")
+			err = testInformerManager.AddResourceWatcher(testClusterID, informer.NewWatcherKey(serviceGVR))
 			Expect(err).NotTo(HaveOccurred())
 
 			// Verify both watchers are active
@@ -213,7 +1603,7 @@ var _ = Describe("InformerManager", func() {
 			defer deleteResource(testNS)
 
 			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
-			err = testInformerManager.AddResourceWatcher(testClusterID, podGVR, "")
+			err = testInformerManager.AddResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR))
 			Expect(err).NotTo(HaveOccurred())
 
 			// Wait for sync and create pod to generate events
@@ -258,7 +1648,7 @@ var _ = Describe("InformerManager", func() {
 			defer deleteResource(testNS)
 
 			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
-			err := testInformerManager.AddResourceWatcher(testClusterID, podGVR, "")
+			err := testInformerManager.AddResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR))
 			Expect(err).NotTo(HaveOccurred())
 
 			time.Sleep(2 * time.Second)
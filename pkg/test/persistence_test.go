@@ -69,7 +69,7 @@ var _ = Describe("ResourceVersion Persistence", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
-			err = manager1.AddResourceWatcher(testClusterID, podGVR, "")
+			err = manager1.AddResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR))
 			Expect(err).NotTo(HaveOccurred())
 
 			// Create test namespace and pod
@@ -111,7 +111,7 @@ var _ = Describe("ResourceVersion Persistence", func() {
 			err = manager2.AddCluster(testClusterID, "test-cluster", kubeconfigPath, "test-context")
 			Expect(err).NotTo(HaveOccurred())
 
-			err = manager2.AddResourceWatcher(testClusterID, podGVR, "")
+			err = manager2.AddResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR))
 			Expect(err).NotTo(HaveOccurred())
 
 			time.Sleep(2 * time.Second)
@@ -175,7 +175,7 @@ var _ = Describe("ResourceVersion Persistence", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
-			err = manager1.AddResourceWatcher(testClusterID, podGVR, "")
+			err = manager1.AddResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR))
 			Expect(err).NotTo(HaveOccurred())
 
 			// Create test namespace
@@ -225,7 +225,7 @@ var _ = Describe("ResourceVersion Persistence", func() {
 			err = manager2.AddCluster(testClusterID, "test-cluster", kubeconfigPath, "test-context")
 			Expect(err).NotTo(HaveOccurred())
 
-			err = manager2.AddResourceWatcher(testClusterID, podGVR, "")
+			err = manager2.AddResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR))
 			Expect(err).NotTo(HaveOccurred())
 
 			time.Sleep(2 * time.Second)
@@ -245,6 +245,72 @@ var _ = Describe("ResourceVersion Persistence", func() {
 			// Verify we have a resource version from the first session
 			Expect(lastResourceVersion).NotTo(BeEmpty())
 		})
+
+		It("should deliver deletions that happened while stopped", func() {
+			kubeconfigPath := writeKubeconfigToTempFile()
+
+			err := manager1.AddCluster(testClusterID, "test-cluster", kubeconfigPath, "test-context")
+			Expect(err).NotTo(HaveOccurred())
+
+			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+			err = manager1.AddResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR))
+			Expect(err).NotTo(HaveOccurred())
+
+			testNS := createTestNamespace("cache-resume-delete-test")
+			Expect(k8sClient.Create(ctx, testNS)).To(Succeed())
+			defer deleteResource(testNS)
+
+			testPod := createTestPod("cache-resume-delete-test", "resume-deleted-pod")
+			Expect(k8sClient.Create(ctx, testPod)).To(Succeed())
+
+			// Wait until the pod has been written to the database cache
+			Eventually(func() bool {
+				resources, _, err := manager1.LoadInitialData(testClusterID, podGVR)
+				if err != nil {
+					return false
+				}
+				for _, resource := range resources {
+					if resource.GetName() == "resume-deleted-pod" {
+						return true
+					}
+				}
+				return false
+			}, 10*time.Second, 500*time.Millisecond).Should(BeTrue())
+
+			manager1.Shutdown()
+			manager1 = nil
+
+			// Delete the pod while nothing is watching
+			deleteResource(testPod)
+
+			manager2 = informer.NewInformerManager(
+				testStorePath,
+				func(event informer.Event) {
+					events2Mutex.Lock()
+					events2 = append(events2, event)
+					events2Mutex.Unlock()
+				},
+			)
+
+			err = manager2.AddCluster(testClusterID, "test-cluster", kubeconfigPath, "test-context")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = manager2.AddResourceWatcher(testClusterID, informer.NewWatcherKey(podGVR))
+			Expect(err).NotTo(HaveOccurred())
+
+			// The resumed watch replays the deletion against the cached pod,
+			// a fresh list would never have seen the pod at all
+			Eventually(func() bool {
+				events2Mutex.RLock()
+				defer events2Mutex.RUnlock()
+				for _, event := range events2 {
+					if event.Type == "DELETED" && event.Name == "resume-deleted-pod" {
+						return true
+					}
+				}
+				return false
+			}, 10*time.Second, time.Second).Should(BeTrue())
+		})
 	})
 
 	Context("Multiple Clusters and Resources", func() {
@@ -265,10 +331,10 @@ var _ = Describe("ResourceVersion Persistence", func() {
 			podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
 			serviceGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "services"}
 
-			err = manager1.AddResourceWatcher(cluster1ID, podGVR, "")
+			err = manager1.AddResourceWatcher(cluster1ID, informer.NewWatcherKey(podGVR))
 			Expect(err).NotTo(HaveOccurred())
 
-			err = manager1.AddResourceWatcher(cluster2ID, serviceGVR, "")
+			err = manager1.AddResourceWatcher(cluster2ID, informer.NewWatcherKey(serviceGVR))
 			Expect(err).NotTo(HaveOccurred())
 
 			time.Sleep(2 * time.Second)
@@ -0,0 +1,217 @@
+package test
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"ksight/pkg/informer"
+	"ksight/pkg/service"
+)
+
+// recordingEmitter captures emitted resource event batches
+type recordingEmitter struct {
+	mu      sync.Mutex
+	batches [][]service.SequencedEvent
+}
+
+func (r *recordingEmitter) Emit(event string, data any) {
+	if event != "resource:events" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, data.([]service.SequencedEvent))
+}
+
+func (r *recordingEmitter) events() []service.SequencedEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []service.SequencedEvent
+	for _, batch := range r.batches {
+		result = append(result, batch...)
+	}
+	return result
+}
+
+func (r *recordingEmitter) batchCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.batches)
+}
+
+var _ = Describe("EventPipeline", func() {
+	var (
+		emitter  *recordingEmitter
+		pipeline *service.EventPipeline
+		config   service.EventPipelineConfig
+		podGVR   = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+	)
+
+	podEvent := func(eventType, name, resourceVersion string) informer.Event {
+		obj := &unstructured.Unstructured{}
+		obj.SetName(name)
+		obj.SetNamespace("default")
+		obj.SetResourceVersion(resourceVersion)
+		return informer.Event{
+			Type:      eventType,
+			ClusterID: "cluster_1",
+			GVR:       podGVR,
+			Namespace: "default",
+			Name:      name,
+			Object:    obj,
+			Timestamp: time.Now(),
+		}
+	}
+
+	BeforeEach(func() {
+		emitter = &recordingEmitter{}
+		config = service.DefaultEventPipelineConfig()
+		config.Window = 200 * time.Millisecond
+	})
+
+	JustBeforeEach(func() {
+		pipeline = service.NewEventPipeline(emitter, config)
+	})
+
+	AfterEach(func() {
+		pipeline.Stop()
+	})
+
+	It("should coalesce events for the same object inside a window", func() {
+		pipeline.Push(podEvent("ADDED", "pod-a", "1"))
+		pipeline.Push(podEvent("MODIFIED", "pod-a", "2"))
+		pipeline.Push(podEvent("MODIFIED", "pod-b", "3"))
+		pipeline.Push(podEvent("MODIFIED", "pod-b", "4"))
+		pipeline.Push(podEvent("ADDED", "pod-c", "5"))
+		pipeline.Push(podEvent("DELETED", "pod-c", "6"))
+
+		Eventually(emitter.events, 2*time.Second).Should(HaveLen(2))
+		Expect(emitter.batchCount()).To(Equal(1))
+
+		events := emitter.events()
+		Expect(events[0].Type).To(Equal("ADDED"))
+		Expect(events[0].Object.GetResourceVersion()).To(Equal("2"))
+		Expect(events[1].Type).To(Equal("MODIFIED"))
+		Expect(events[1].Object.GetResourceVersion()).To(Equal("4"))
+		Expect(events[0].Sequence).To(BeNumerically("<", events[1].Sequence))
+	})
+
+	Context("with a small batch size", func() {
+		BeforeEach(func() {
+			config.Window = time.Hour
+			config.MaxBatchSize = 10
+		})
+
+		It("should emit full batches without waiting for the window", func() {
+			for i := 0; i < 25; i++ {
+				pipeline.Push(podEvent("ADDED", "pod-"+string(rune('a'+i)), "1"))
+			}
+
+			Eventually(emitter.batchCount, 2*time.Second).Should(BeNumerically(">=", 2))
+			pipeline.Stop()
+
+			events := emitter.events()
+			Expect(events).To(HaveLen(25))
+			for i := 1; i < len(events); i++ {
+				Expect(events[i].Sequence).To(Equal(events[i-1].Sequence + 1))
+			}
+		})
+	})
+
+	Context("when the frontend falls behind", func() {
+		BeforeEach(func() {
+			config.MaxInFlight = 2
+			config.AckTimeout = time.Hour
+		})
+
+		It("should hold back batches until events are acknowledged", func() {
+			pipeline.Push(podEvent("ADDED", "pod-a", "1"))
+			pipeline.Push(podEvent("ADDED", "pod-b", "2"))
+			Eventually(emitter.events, 2*time.Second).Should(HaveLen(2))
+
+			pipeline.Push(podEvent("MODIFIED", "pod-a", "3"))
+			pipeline.Push(podEvent("MODIFIED", "pod-a", "4"))
+			Consistently(emitter.events, time.Second).Should(HaveLen(2))
+
+			pipeline.Ack(emitter.events()[1].Sequence)
+			Eventually(emitter.events, 2*time.Second).Should(HaveLen(3))
+			Expect(emitter.events()[2].Object.GetResourceVersion()).To(Equal("4"))
+		})
+	})
+
+	Context("with patch encoding", func() {
+		BeforeEach(func() {
+			config.Window = 50 * time.Millisecond
+			config.Encoding = service.EventEncodingPatch
+			config.SnapshotInterval = 2
+		})
+
+		modified := func(old informer.Event, resourceVersion, phase string) informer.Event {
+			event := podEvent("MODIFIED", old.Name, resourceVersion)
+			event.Object.SetUID("pod-a-uid")
+			Expect(unstructured.SetNestedField(event.Object.Object, phase, "status", "phase")).To(Succeed())
+			event.OldObject = old.Object
+			return event
+		}
+
+		It("should send updates as patches with full snapshots in between", func() {
+			added := podEvent("ADDED", "pod-a", "1")
+			added.Object.SetUID("pod-a-uid")
+			pipeline.Push(added)
+			Eventually(emitter.events, 2*time.Second).Should(HaveLen(1))
+
+			// Updates are sent apart so that they are not coalesced
+			pending := modified(added, "2", "Pending")
+			pipeline.Push(pending)
+			Eventually(emitter.events, 2*time.Second).Should(HaveLen(2))
+			running := modified(pending, "3", "Running")
+			pipeline.Push(running)
+			Eventually(emitter.events, 2*time.Second).Should(HaveLen(3))
+			pipeline.Push(modified(running, "4", "Succeeded"))
+			Eventually(emitter.events, 2*time.Second).Should(HaveLen(4))
+
+			events := emitter.events()
+			Expect(events[0].Object).NotTo(BeNil())
+			Expect(events[0].Patch).To(BeEmpty())
+
+			Expect(events[1].Object).To(BeNil())
+			Expect(events[1].OldObject).To(BeNil())
+			Expect(events[1].BaseResourceVersion).To(Equal("1"))
+			var patch map[string]any
+			Expect(json.Unmarshal(events[1].Patch, &patch)).To(Succeed())
+			Expect(patch).To(Equal(map[string]any{
+				"metadata": map[string]any{"resourceVersion": "2"},
+				"status":   map[string]any{"phase": "Pending"},
+			}))
+			Expect(events[2].BaseResourceVersion).To(Equal("2"))
+
+			// Every third update is a full snapshot
+			Expect(events[3].Patch).To(BeEmpty())
+			Expect(events[3].OldObject).To(BeNil())
+			Expect(events[3].Object.GetResourceVersion()).To(Equal("4"))
+		})
+
+		It("should send resynced objects in full", func() {
+			added := podEvent("ADDED", "pod-a", "1")
+			added.Object.SetUID("pod-a-uid")
+			pipeline.Push(added)
+			Eventually(emitter.events, 2*time.Second).Should(HaveLen(1))
+
+			// The frontend missed the base of the pending update and asks for the object
+			pending := modified(added, "2", "Pending")
+			pipeline.Push(pending)
+			pipeline.Resync(pending)
+			Eventually(emitter.events, 2*time.Second).Should(HaveLen(2))
+
+			events := emitter.events()
+			Expect(events[1].Patch).To(BeEmpty())
+			Expect(events[1].Object.GetResourceVersion()).To(Equal("2"))
+		})
+	})
+})
@@ -3,6 +3,9 @@ package test
 import (
 	"context"
 	"fmt"
+	"net"
+	"strings"
+	"sync"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -109,8 +112,9 @@ var _ = Describe("ClusterService", func() {
 				Resource:  "pods",
 			}
 
-			err := testService.AddResourceWatcher(request)
+			subscriptionID, err := testService.AddResourceWatcher(request)
 			Expect(err).NotTo(HaveOccurred())
+			Expect(subscriptionID).NotTo(BeEmpty())
 		})
 
 		It("should remove resource watcher", func() {
@@ -121,11 +125,166 @@ var _ = Describe("ClusterService", func() {
 				Resource:  "pods",
 			}
 
-			err := testService.AddResourceWatcher(request)
+			subscriptionID, err := testService.AddResourceWatcher(request)
 			Expect(err).NotTo(HaveOccurred())
 
-			err = testService.RemoveResourceWatcher(request)
+			err = testService.RemoveResourceWatcher(subscriptionID)
 			Expect(err).NotTo(HaveOccurred())
+
+			err = testService.RemoveResourceWatcher(subscriptionID)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should share a watcher between subscriptions", func() {
+			testService.SetWatcherGracePeriod(500 * time.Millisecond)
+
+			request := service.ResourceWatchRequest{
+				ClusterID: clusterID,
+				Group:     "",
+				Version:   "v1",
+				Resource:  "pods",
+			}
+
+			first, err := testService.AddResourceWatcher(request)
+			Expect(err).NotTo(HaveOccurred())
+			second, err := testService.AddResourceWatcher(request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second).NotTo(Equal(first))
+
+			watchers := testService.GetResourceWatchers(clusterID)
+			Expect(watchers).To(HaveLen(1))
+			Expect(watchers[0].Subscriptions).To(Equal(2))
+
+			// The watcher outlives the first subscription
+			Expect(testService.RemoveResourceWatcher(first)).To(Succeed())
+			Consistently(func() int {
+				return len(testService.GetResourceWatchers(clusterID))
+			}, time.Second).Should(Equal(1))
+
+			// An idle watcher survives the grace period only when resubscribed
+			Expect(testService.RemoveResourceWatcher(second)).To(Succeed())
+			Expect(testService.GetResourceWatchers(clusterID)[0].Idle).To(BeTrue())
+
+			third, err := testService.AddResourceWatcher(request)
+			Expect(err).NotTo(HaveOccurred())
+			Consistently(func() int {
+				return len(testService.GetResourceWatchers(clusterID))
+			}, time.Second).Should(Equal(1))
+
+			Expect(testService.RemoveResourceWatcher(third)).To(Succeed())
+			Eventually(func() int {
+				return len(testService.GetResourceWatchers(clusterID))
+			}, 5*time.Second).Should(BeZero())
+			Expect(testService.GetClusters()).To(HaveKey(clusterID))
+		})
+
+		It("should keep subscribing while a watcher of an unresponsive cluster starts", func() {
+			// The apiserver of this cluster accepts connections but never answers
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			var conns []net.Conn
+			var connMutex sync.Mutex
+			go func() {
+				for {
+					conn, err := listener.Accept()
+					if err != nil {
+						return
+					}
+					connMutex.Lock()
+					conns = append(conns, conn)
+					connMutex.Unlock()
+				}
+			}()
+			closeAll := func() {
+				listener.Close()
+				connMutex.Lock()
+				defer connMutex.Unlock()
+				for _, conn := range conns {
+					conn.Close()
+				}
+			}
+			defer closeAll()
+
+			kubeconfig := strings.Replace(getKubeconfigContent(), cfg.Host, "https://"+listener.Addr().String(), 1)
+			stuckID, err := testService.AddCluster("stuck-cluster", kubeconfig, "test-context")
+			Expect(err).NotTo(HaveOccurred())
+
+			stuckDone := make(chan error, 1)
+			go func() {
+				_, err := testService.AddResourceWatcher(service.ResourceWatchRequest{ClusterID: stuckID, Version: "v1", Resource: "pods"})
+				stuckDone <- err
+			}()
+			Eventually(func() int {
+				return len(testService.GetResourceWatchers(stuckID))
+			}, 5*time.Second).Should(Equal(1))
+
+			done := make(chan error, 1)
+			go func() {
+				_, err := testService.AddResourceWatcher(service.ResourceWatchRequest{ClusterID: clusterID, Version: "v1", Resource: "pods"})
+				done <- err
+			}()
+			Eventually(done, 5*time.Second).Should(Receive(BeNil()))
+			Expect(testService.GetResourceWatchers(clusterID)).To(HaveLen(1))
+
+			// The start fails once the connections break, its slot is released
+			closeAll()
+			Eventually(stuckDone, 30*time.Second).Should(Receive(HaveOccurred()))
+			Expect(testService.GetResourceWatchers(stuckID)).To(BeEmpty())
+		})
+
+		It("should move a shared subscription to its own watcher when rescoped", func() {
+			testService.SetWatcherGracePeriod(0)
+
+			request := service.ResourceWatchRequest{
+				ClusterID:  clusterID,
+				Group:      "",
+				Version:    "v1",
+				Resource:   "pods",
+				Namespaces: []string{"default"},
+			}
+
+			first, err := testService.AddResourceWatcher(request)
+			Expect(err).NotTo(HaveOccurred())
+			second, err := testService.AddResourceWatcher(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			updated, err := testService.AddWatcherNamespace(second, "kube-system")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated.Namespaces).To(Equal([]string{"default", "kube-system"}))
+			Expect(testService.GetResourceWatchers(clusterID)).To(HaveLen(2))
+
+			// Once exclusive, the watcher is rescoped in place
+			Expect(testService.RemoveResourceWatcher(first)).To(Succeed())
+			Expect(testService.GetResourceWatchers(clusterID)).To(HaveLen(1))
+
+			updated, err = testService.RemoveWatcherNamespace(second, "default")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated.Namespaces).To(Equal([]string{"kube-system"}))
+			Expect(testService.GetResourceWatchers(clusterID)).To(HaveLen(1))
+		})
+
+		It("should report watcher status with subscription counts", func() {
+			request := service.ResourceWatchRequest{
+				ClusterID: clusterID,
+				Group:     "",
+				Version:   "v1",
+				Resource:  "pods",
+			}
+
+			_, err := testService.AddResourceWatcher(request)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = testService.AddResourceWatcher(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() []service.WatcherStatus {
+				statuses, err := testService.GetWatcherStatus(clusterID)
+				Expect(err).NotTo(HaveOccurred())
+				return statuses
+			}, 10*time.Second).Should(ConsistOf(And(
+				HaveField("Resource", "pods"),
+				HaveField("Subscriptions", 2),
+				HaveField("HasSynced", BeTrue()),
+			)))
 		})
 
 		It("should get resource types for cluster", func() {
@@ -155,14 +314,16 @@ var _ = Describe("ClusterService", func() {
 				{ClusterID: clusterID, Group: "apps", Version: "v1", Resource: "deployments"},
 			}
 
+			var subscriptionIDs []string
 			for _, request := range requests {
-				err := testService.AddResourceWatcher(request)
+				subscriptionID, err := testService.AddResourceWatcher(request)
 				Expect(err).NotTo(HaveOccurred())
+				subscriptionIDs = append(subscriptionIDs, subscriptionID)
 			}
 
 			// Remove all watchers
-			for _, request := range requests {
-				err := testService.RemoveResourceWatcher(request)
+			for _, subscriptionID := range subscriptionIDs {
+				err := testService.RemoveResourceWatcher(subscriptionID)
 				Expect(err).NotTo(HaveOccurred())
 			}
 		})
@@ -228,7 +389,7 @@ var _ = Describe("ClusterService", func() {
 				Resource:  "pods",
 			}
 
-			err := testService.AddResourceWatcher(request)
+			_, err := testService.AddResourceWatcher(request)
 			Expect(err).NotTo(HaveOccurred())
 
 			// Wait for informer to sync
@@ -255,7 +416,7 @@ var _ = Describe("ClusterService", func() {
 			}
 
 			for _, request := range requests {
-				err := testService.AddResourceWatcher(request)
+				_, err := testService.AddResourceWatcher(request)
 				Expect(err).NotTo(HaveOccurred())
 			}
 
@@ -277,7 +438,7 @@ var _ = Describe("ClusterService", func() {
 				Resource:  "pods",
 			}
 
-			err := testService.AddResourceWatcher(request)
+			_, err := testService.AddResourceWatcher(request)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("not found"))
 		})
@@ -4,8 +4,13 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -14,7 +19,10 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -182,6 +190,35 @@ func createTestDeployment(namespace, name string, replicas int32) *appsv1.Deploy
 	}
 }
 
+func createTestReplicaSet(owner *appsv1.Deployment, name string) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       owner.Namespace,
+			Labels:          owner.Spec.Template.Labels,
+			OwnerReferences: []metav1.OwnerReference{ownerReference(owner, "apps/v1", "Deployment")},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Replicas: owner.Spec.Replicas,
+			Selector: owner.Spec.Selector,
+			Template: owner.Spec.Template,
+		},
+	}
+}
+
+// ownerReference returns a controller reference to a created object, envtest runs
+// no controllers so tests set up ownership themselves
+func ownerReference(owner client.Object, apiVersion, kind string) metav1.OwnerReference {
+	controller := true
+	return metav1.OwnerReference{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Name:       owner.GetName(),
+		UID:        owner.GetUID(),
+		Controller: &controller,
+	}
+}
+
 func createTestNamespace(name string) *corev1.Namespace {
 	return &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
@@ -190,6 +227,51 @@ func createTestNamespace(name string) *corev1.Namespace {
 	}
 }
 
+func createTestCRD(group, kind, plural string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apiextensions.k8s.io/v1",
+			"kind":       "CustomResourceDefinition",
+			"metadata": map[string]any{
+				"name": plural + "." + group,
+			},
+			"spec": map[string]any{
+				"group": group,
+				"scope": "Namespaced",
+				"names": map[string]any{
+					"kind":     kind,
+					"listKind": kind + "List",
+					"plural":   plural,
+					"singular": strings.ToLower(kind),
+				},
+				"versions": []any{
+					map[string]any{
+						"name":    "v1",
+						"served":  true,
+						"storage": true,
+						"schema": map[string]any{
+							"openAPIV3Schema": map[string]any{
+								"type":                                 "object",
+								"x-kubernetes-preserve-unknown-fields": true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func waitForServed(gvr schema.GroupVersionResource, timeout time.Duration) {
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	Expect(err).NotTo(HaveOccurred())
+
+	Eventually(func() error {
+		_, err := dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+		return err
+	}, timeout, time.Second).Should(Succeed())
+}
+
 func waitForResource(obj client.Object, timeout time.Duration) {
 	Eventually(func() error {
 		return k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj)
@@ -211,15 +293,19 @@ func deleteResource(obj client.Object) {
 }
 
 func getKubeconfigContent() string {
-	// Generate kubeconfig content for the test cluster
+	return kubeconfigContentFor(cfg)
+}
+
+// kubeconfigContentFor generates kubeconfig content for a user of the test cluster
+func kubeconfigContentFor(config *rest.Config) string {
 	var userConfig string
-	if cfg.BearerToken != "" {
-		userConfig = fmt.Sprintf("token: %s", cfg.BearerToken)
-	} else if len(cfg.CertData) > 0 && len(cfg.KeyData) > 0 {
+	if config.BearerToken != "" {
+		userConfig = fmt.Sprintf("token: %s", config.BearerToken)
+	} else if len(config.CertData) > 0 && len(config.KeyData) > 0 {
 		userConfig = fmt.Sprintf(`client-certificate-data: %s
     client-key-data: %s`,
-			base64.StdEncoding.EncodeToString(cfg.CertData),
-			base64.StdEncoding.EncodeToString(cfg.KeyData))
+			base64.StdEncoding.EncodeToString(config.CertData),
+			base64.StdEncoding.EncodeToString(config.KeyData))
 	} else {
 		// Fallback to insecure for test environment
 		userConfig = "token: test-token"
@@ -243,8 +329,8 @@ users:
   user:
     %s
 `,
-		base64.StdEncoding.EncodeToString(cfg.CAData),
-		cfg.Host,
+		base64.StdEncoding.EncodeToString(config.CAData),
+		config.Host,
 		userConfig,
 	)
 }
@@ -262,3 +348,81 @@ func writeKubeconfigToTempFile() string {
 
 	return tmpFile.Name()
 }
+
+// testProxy forwards TCP connections to the test apiserver and can be taken down
+// to simulate an outage
+type testProxy struct {
+	address  string
+	target   string
+	listener net.Listener
+	conns    []net.Conn
+	mu       sync.Mutex
+}
+
+// startTestProxy starts a proxy to the test apiserver on a free local port
+func startTestProxy() *testProxy {
+	serverURL, err := url.Parse(cfg.Host)
+	Expect(err).NotTo(HaveOccurred())
+
+	proxy := &testProxy{address: "127.0.0.1:0", target: serverURL.Host}
+	proxy.Start()
+	proxy.address = proxy.listener.Addr().String()
+	return proxy
+}
+
+// Start accepts connections again, on the same address
+func (p *testProxy) Start() {
+	listener, err := net.Listen("tcp", p.address)
+	Expect(err).NotTo(HaveOccurred())
+
+	p.mu.Lock()
+	p.listener = listener
+	p.mu.Unlock()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			upstream, err := net.Dial("tcp", p.target)
+			if err != nil {
+				conn.Close()
+				continue
+			}
+
+			p.mu.Lock()
+			p.conns = append(p.conns, conn, upstream)
+			p.mu.Unlock()
+
+			go func() {
+				io.Copy(upstream, conn)
+				upstream.Close()
+			}()
+			go func() {
+				io.Copy(conn, upstream)
+				conn.Close()
+			}()
+		}
+	}()
+}
+
+// Stop refuses new connections and breaks the open ones
+func (p *testProxy) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.listener.Close()
+	for _, conn := range p.conns {
+		conn.Close()
+	}
+	p.conns = nil
+}
+
+// kubeconfig writes a kubeconfig for the test apiserver behind the proxy
+func (p *testProxy) kubeconfig() string {
+	content := strings.Replace(getKubeconfigContent(), cfg.Host, "https://"+p.address, 1)
+	kubeconfigPath := filepath.Join(tempDir, "proxy-kubeconfig.yaml")
+	Expect(os.WriteFile(kubeconfigPath, []byte(content), 0600)).To(Succeed())
+	return kubeconfigPath
+}